@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"devstation-cli/pkg/cache"
+	"devstation-cli/pkg/installer"
+	"devstation-cli/pkg/python"
+)
+
+// cacheCmd groups the offline package cache commands
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Manage the offline package cache",
+	Long:  `Build a local package cache that 'devstation setup --offline --cache DIR' installs from on machines without internet access.`,
+}
+
+// cacheBuildCmd downloads everything the setup commands install into a cache directory
+var cacheBuildCmd = &cobra.Command{
+	Use:   "build",
+	Short: "Download packages into a local cache",
+	Long: `Download every Python tool package (plus packages declared in --manifest requirement files)
+into a local wheelhouse and record the system packages the setup commands install.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		dir, _ := cmd.Flags().GetString("dir")
+		manifests, _ := cmd.Flags().GetStringSlice("manifest")
+		if err := buildCache(dir, manifests); err != nil {
+			fmt.Printf("Error building cache: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+// buildCache collects the packages to cache and downloads them into dir
+func buildCache(dir string, manifests []string) error {
+	pm := installer.GetAvailablePackageManager()
+	if pm == nil {
+		return fmt.Errorf("no package manager available")
+	}
+
+	pipPackages := append([]string{}, python.GlobalTools...)
+	for _, path := range manifests {
+		requirements, err := cache.ReadRequirements(path)
+		if err != nil {
+			return fmt.Errorf("failed to read manifest %s: %v", path, err)
+		}
+		pipPackages = append(pipPackages, requirements...)
+	}
+
+	fmt.Printf("Building package cache in %s...\n", dir)
+	manifest, err := cache.Build(dir, pm, python.NewPythonSetup(pm), pipPackages, managedSystemPackages())
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("✓ Cached %d pip package(s) and %d system package(s)\n", len(manifest.Python), len(manifest.System))
+	fmt.Printf("To install offline, run:\n")
+	fmt.Printf("  devstation setup all --offline --cache %s\n", dir)
+	return nil
+}
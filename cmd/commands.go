@@ -4,9 +4,14 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"strings"
 
 	"github.com/spf13/cobra"
+	"devstation-cli/pkg/cache"
+	"devstation-cli/pkg/config"
 	"devstation-cli/pkg/installer"
+	"devstation-cli/pkg/project"
 	"devstation-cli/pkg/python"
 	"devstation-cli/pkg/cdev"
 )
@@ -22,9 +27,9 @@ var setupCmd = &cobra.Command{
 var pythonCmd = &cobra.Command{
 	Use:   "python",
 	Short: "Set up Python development environment",
-	Long:  `Install Python, pip, and essential packages for Python development.`,
+	Long:  `Install Python, pip, and isolated Python tools (black, flake8, pytest, pip-tools) for Python development.`,
 	Run: func(cmd *cobra.Command, args []string) {
-		if err := setupPythonEnvironment(); err != nil {
+		if err := setupPythonEnvironment(setupOptionsFromFlags(cmd)); err != nil {
 			fmt.Printf("Error setting up Python environment: %v\n", err)
 			os.Exit(1)
 		}
@@ -37,7 +42,7 @@ var cCmd = &cobra.Command{
 	Short: "Set up C development environment",
 	Long:  `Install C compiler (MinGW or MSVC), build tools, and development utilities.`,
 	Run: func(cmd *cobra.Command, args []string) {
-		if err := setupCEnvironment(); err != nil {
+		if err := setupCEnvironment(setupOptionsFromFlags(cmd)); err != nil {
 			fmt.Printf("Error setting up C environment: %v\n", err)
 			os.Exit(1)
 		}
@@ -52,12 +57,12 @@ var allCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		fmt.Println("Setting up complete development environment...")
 		
-		if err := setupPythonEnvironment(); err != nil {
+		if err := setupPythonEnvironment(setupOptionsFromFlags(cmd)); err != nil {
 			fmt.Printf("Error setting up Python environment: %v\n", err)
 			os.Exit(1)
 		}
 		
-		if err := setupCEnvironment(); err != nil {
+		if err := setupCEnvironment(setupOptionsFromFlags(cmd)); err != nil {
 			fmt.Printf("Error setting up C environment: %v\n", err)
 			os.Exit(1)
 		}
@@ -77,10 +82,21 @@ var newCmd = &cobra.Command{
 var newPythonCmd = &cobra.Command{
 	Use:   "python [project-name]",
 	Short: "Create a new Python project",
-	Long:  `Create a new Python project with proper structure, virtual environment, and configuration files.`,
+	Long:  `Create a new Python project with a PEP 621 pyproject.toml, a src/<package> layout, a virtual environment and tool configuration.`,
 	Args:  cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		projectName := args[0]
+		scaffold := scaffoldOptions(cmd, project.KindPython, func(name string) bool {
+			_, ok := python.ProjectTemplates[name]
+			return ok
+		})
+		backend, _ := cmd.Flags().GetString("backend")
+		template, _ := cmd.Flags().GetString("template")
+		noInstall, _ := cmd.Flags().GetBool("no-install")
+		manager, _ := cmd.Flags().GetString("manager")
+		preCommit, _ := cmd.Flags().GetBool("pre-commit")
+		matrix, _ := cmd.Flags().GetStringSlice("matrix")
+		matrixTool, _ := cmd.Flags().GetString("matrix-tool")
 		
 		pm := installer.GetAvailablePackageManager()
 		if pm == nil {
@@ -89,7 +105,8 @@ var newPythonCmd = &cobra.Command{
 		}
 		
 		pythonSetup := python.NewPythonSetup(pm)
-		if err := pythonSetup.CreateProjectStructure(projectName); err != nil {
+		opts := python.ProjectOptions{Backend: backend, Template: template, Manager: manager, PreCommit: preCommit, NoInstall: noInstall, Matrix: matrix, MatrixTool: matrixTool, Options: scaffold}
+		if err := pythonSetup.CreateProjectStructure(projectName, opts); err != nil {
 			fmt.Printf("Error creating Python project: %v\n", err)
 			os.Exit(1)
 		}
@@ -112,13 +129,75 @@ var newCCmd = &cobra.Command{
 		}
 		
 		cSetup := cdev.NewCDevSetup(pm)
-		if err := cSetup.CreateCProject(projectName); err != nil {
+		if err := cSetup.CreateCProject(projectName, scaffoldOptions(cmd, project.KindC, func(name string) bool {
+			return name == cdev.DefaultTemplate
+		})); err != nil {
 			fmt.Printf("Error creating C project: %v\n", err)
 			os.Exit(1)
 		}
 	},
 }
 
+// scaffoldOptions reads the scaffolding flags shared by the new commands. --template-dir, or
+// a --template that is not built in, selects a user-defined template of the given kind.
+func scaffoldOptions(cmd *cobra.Command, kind string, builtin func(name string) bool) project.Options {
+	author, _ := cmd.Flags().GetString("author")
+	license, _ := cmd.Flags().GetString("license")
+	templateDir, _ := cmd.Flags().GetString("template-dir")
+	name, _ := cmd.Flags().GetString("template")
+	given, _ := cmd.Flags().GetStringToString("var")
+	opts := project.Options{Mode: scaffoldMode(cmd), Author: author, License: license}
+	
+	var sources []string
+	switch {
+	case templateDir != "":
+		sources = []string{templateDir}
+		if !cmd.Flags().Changed("template") {
+			name = ""
+		}
+	case !builtin(name):
+		sources = project.TemplateSources()
+	default:
+		if len(given) > 0 {
+			fmt.Println("Error: --var only applies to user-defined templates")
+			os.Exit(1)
+		}
+		return opts
+	}
+	
+	t, err := project.FindTemplate(kind, name, sources)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+	vars, err := project.ResolveVars(t.Variables, given, stdinIsTerminal())
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+	opts.UserTemplate = &t
+	opts.Vars = vars
+	return opts
+}
+
+// scaffoldMode reads the --force, --merge and --interactive flags of the new commands
+func scaffoldMode(cmd *cobra.Command) project.Mode {
+	var modes []project.Mode
+	for _, mode := range []project.Mode{project.ModeForce, project.ModeMerge, project.ModeInteractive} {
+		if set, _ := cmd.Flags().GetBool(string(mode)); set {
+			modes = append(modes, mode)
+		}
+	}
+	if len(modes) > 1 {
+		fmt.Println("Error: --force, --merge and --interactive cannot be combined")
+		os.Exit(1)
+	}
+	if len(modes) == 1 {
+		return modes[0]
+	}
+	return project.ModeAbort
+}
+
 // statusCmd shows the current development environment status
 var statusCmd = &cobra.Command{
 	Use:   "status",
@@ -129,27 +208,56 @@ var statusCmd = &cobra.Command{
 	},
 }
 
+// setupOptions holds the flags shared by the setup commands
+type setupOptions struct {
+	Bootstrap installer.BootstrapOptions
+	Offline   bool
+	CacheDir  string
+	Python    string   // Python version to install
+	Managers  []string // Project managers to install as tools
+}
+
 // setupPythonEnvironment sets up the Python development environment
-func setupPythonEnvironment() error {
+func setupPythonEnvironment(opts setupOptions) error {
 	fmt.Println("🐍 Setting up Python development environment...")
 	
-	// Ensure package manager is available
-	if err := installer.InstallPackageManager(); err != nil {
-		return fmt.Errorf("failed to install package manager: %v", err)
+	systemPackages := append([]string{"python"}, python.DevelopmentTools...)
+	if opts.Python != "" {
+		systemPackages = python.DevelopmentTools
 	}
-	
-	pm := installer.GetAvailablePackageManager()
-	if pm == nil {
-		return fmt.Errorf("no package manager available")
+	pipPackages := append([]string{}, python.GlobalTools...)
+	for _, name := range opts.Managers {
+		manager, ok := python.ProjectManagers[name]
+		if !ok {
+			return fmt.Errorf("unknown project manager %q; expected one of %s", name, strings.Join(python.ProjectManagerNames(), ", "))
+		}
+		if manager.Package != "" {
+			pipPackages = append(pipPackages, manager.Package)
+		}
+	}
+	pm, err := preparePackageManager(opts, pipPackages, systemPackages)
+	if err != nil {
+		return err
 	}
 	
 	pythonSetup := python.NewPythonSetup(pm)
+	pythonSetup.Version = opts.Python
+	if opts.Offline {
+		pythonSetup.WheelDir = filepath.Join(opts.CacheDir, cache.WheelDir)
+	}
 	
-	// Install Python and essential packages
+	// Install Python and the Python tools
 	if err := pythonSetup.InstallPython(); err != nil {
 		return err
 	}
 	
+	// Install the selected project managers
+	for _, name := range opts.Managers {
+		if err := pythonSetup.EnsureProjectManager(name); err != nil {
+			fmt.Printf("Warning: %v\n", err)
+		}
+	}
+	
 	// Install development tools
 	if err := pythonSetup.InstallPythonTools(); err != nil {
 		return err
@@ -159,17 +267,13 @@ func setupPythonEnvironment() error {
 }
 
 // setupCEnvironment sets up the C development environment
-func setupCEnvironment() error {
+func setupCEnvironment(opts setupOptions) error {
 	fmt.Println("⚙️  Setting up C development environment...")
 	
-	// Ensure package manager is available
-	if err := installer.InstallPackageManager(); err != nil {
-		return fmt.Errorf("failed to install package manager: %v", err)
-	}
-	
-	pm := installer.GetAvailablePackageManager()
-	if pm == nil {
-		return fmt.Errorf("no package manager available")
+	systemPackages := append([]string{cdev.CompilerPackages[0]}, cdev.DevelopmentTools...)
+	pm, err := preparePackageManager(opts, nil, systemPackages)
+	if err != nil {
+		return err
 	}
 	
 	cSetup := cdev.NewCDevSetup(pm)
@@ -182,19 +286,95 @@ func setupCEnvironment() error {
 	return nil
 }
 
+// preparePackageManager makes sure a package manager is available and, in offline mode,
+// wraps it to install from the local cache after checking the cache has everything needed
+func preparePackageManager(opts setupOptions, pipPackages, systemPackages []string) (installer.PackageManager, error) {
+	// Ensure package manager is available
+	if err := installer.InstallPackageManager(opts.Bootstrap); err != nil {
+		return nil, fmt.Errorf("failed to install package manager: %v", err)
+	}
+	
+	pm := installer.GetAvailablePackageManager()
+	if pm == nil {
+		return nil, fmt.Errorf("no package manager available")
+	}
+	
+	if !opts.Offline {
+		return pm, nil
+	}
+	
+	if opts.CacheDir == "" {
+		return nil, fmt.Errorf("--offline requires --cache DIR")
+	}
+	manifest, err := cache.Load(opts.CacheDir)
+	if err != nil {
+		return nil, err
+	}
+	
+	if missing := manifest.Missing(opts.CacheDir, pm, pipPackages, systemPackages); len(missing) > 0 {
+		fmt.Println("The offline cache is missing these packages:")
+		for _, pkg := range missing {
+			fmt.Printf("  ✗ %s\n", pkg)
+		}
+		return nil, fmt.Errorf("offline cache %s is incomplete; rebuild it with 'devstation cache build'", opts.CacheDir)
+	}
+	
+	fmt.Printf("Installing from offline cache %s\n", opts.CacheDir)
+	return &cache.OfflineManager{PackageManager: pm, Dir: opts.CacheDir, Manifest: manifest}, nil
+}
+
+// setupOptionsFromFlags reads the flags shared by the setup commands
+func setupOptionsFromFlags(cmd *cobra.Command) setupOptions {
+	allow, _ := cmd.Flags().GetBool("allow-bootstrap")
+	manager, _ := cmd.Flags().GetString("bootstrap-manager")
+	source, _ := cmd.Flags().GetString("bootstrap-script")
+	checksum, _ := cmd.Flags().GetString("bootstrap-sha256")
+	offline, _ := cmd.Flags().GetBool("offline")
+	cacheDir, _ := cmd.Flags().GetString("cache")
+	pythonVersion, _ := cmd.Flags().GetString("version")
+	managers, _ := cmd.Flags().GetStringSlice("manager")
+	
+	return setupOptions{
+		Bootstrap: installer.BootstrapOptions{
+			Allow:   allow,
+			Manager: manager,
+			Source:  source,
+			SHA256:  checksum,
+		},
+		Offline:  offline,
+		CacheDir: cacheDir,
+		Python:   pythonVersion,
+		Managers: managers,
+	}
+}
+
 // checkEnvironmentStatus checks and displays the current environment status
 func checkEnvironmentStatus() {
 	fmt.Println("=== Development Environment Status ===")
 	
+	// Check privileges
+	if installer.IsElevated() {
+		fmt.Println("\nPrivileges: elevated (administrator/root)")
+	} else if installer.CanElevate() {
+		fmt.Println("\nPrivileges: standard user (sudo available for system packages)")
+	} else {
+		fmt.Println("\nPrivileges: standard user (user-scoped installs only)")
+	}
+	
 	// Check package managers
 	fmt.Println("\nPackage Managers:")
 	checkCommand("winget", "Windows Package Manager")
 	checkCommand("choco", "Chocolatey")
+	checkCommand("scoop", "Scoop")
+	checkCommand("apt-get", "apt")
 	
 	// Check Python environment
 	fmt.Println("\nPython Environment:")
 	checkCommand("python", "Python")
 	checkCommand("pip", "pip")
+	if path := config.Current().Python.Default; path != "" {
+		fmt.Printf("  Default interpreter: %s\n", path)
+	}
 	
 	// Check C environment
 	fmt.Println("\nC Development Environment:")
@@ -235,8 +415,105 @@ func InitCommands(rootCmd *cobra.Command) {
 	newCmd.AddCommand(newPythonCmd)
 	newCmd.AddCommand(newCCmd)
 	
+	// Add cache subcommands
+	cacheCmd.AddCommand(cacheBuildCmd)
+	
+	// Add config subcommands
+	configCmd.AddCommand(configListCmd)
+	configCmd.AddCommand(configGetCmd)
+	configCmd.AddCommand(configSetCmd)
+	configCmd.AddCommand(configUnsetCmd)
+	configCmd.AddCommand(configPathCmd)
+	
+	// Add Python interpreter subcommands
+	pythonInterpretersCmd.AddCommand(pythonListCmd)
+	pythonInterpretersCmd.AddCommand(pythonInstallCmd)
+	pythonInterpretersCmd.AddCommand(pythonUseCmd)
+	pythonInterpretersCmd.AddCommand(pythonFindCmd)
+	
+	// Add virtual environment subcommands
+	venvCmd.AddCommand(venvCreateCmd)
+	venvCmd.AddCommand(venvRecreateCmd)
+	venvCmd.AddCommand(venvDeleteCmd)
+	venvCmd.AddCommand(venvInfoCmd)
+	venvCmd.AddCommand(venvExecCmd)
+	venvCmd.AddCommand(venvKernelCmd)
+	venvKernelCmd.AddCommand(venvKernelListCmd)
+	venvKernelCmd.AddCommand(venvKernelPruneCmd)
+	
+	// Add Python tool subcommands
+	toolsCmd.AddCommand(toolsListCmd)
+	toolsCmd.AddCommand(toolsInstallCmd)
+	toolsCmd.AddCommand(toolsUninstallCmd)
+	
+	// Add project template subcommands
+	templatesCmd.AddCommand(templatesListCmd)
+	templatesCmd.AddCommand(templatesShowCmd)
+	templatesCmd.AddCommand(templatesValidateCmd)
+	
+	// Add command flags
+	pythonCmd.Flags().String("version", "", "Python version to install (e.g. 3.11)")
+	pythonCmd.Flags().StringSlice("manager", nil, "Project managers to install as tools: poetry, uv, hatch, pipenv (repeatable)")
+	newPythonCmd.Flags().String("backend", "", "Build backend: setuptools, hatchling, flit or poetry (default: the project manager's backend)")
+	newPythonCmd.Flags().String("template", python.DefaultTemplate, "Project template: "+strings.Join(python.TemplateNames(), ", ")+", or a user-defined template")
+	newCCmd.Flags().String("template", cdev.DefaultTemplate, "Project template: "+cdev.DefaultTemplate+", or a user-defined template")
+	newPythonCmd.Flags().String("manager", python.DefaultProjectManager, "Project manager: "+strings.Join(python.ProjectManagerNames(), ", "))
+	newPythonCmd.Flags().Bool("pre-commit", false, "Add pre-commit hooks and flake8 configuration, and install the hooks")
+	newPythonCmd.Flags().Bool("no-install", false, "Create the virtual environment without installing the project and its dependencies")
+	newPythonCmd.Flags().StringSlice("matrix", nil, "Python versions to generate a test matrix for (e.g. 3.9,3.10,3.11,3.12)")
+	newPythonCmd.Flags().String("matrix-tool", python.DefaultMatrixTool, "Test matrix tool: "+strings.Join(python.MatrixTools, " or "))
+	newCmd.PersistentFlags().Bool("force", false, "Scaffold into an existing directory, overwriting files that differ")
+	newCmd.PersistentFlags().Bool("merge", false, "Scaffold into an existing directory, only adding missing files")
+	newCmd.PersistentFlags().Bool("interactive", false, "Scaffold into an existing directory, showing a diff and asking before changing each file")
+	newCmd.PersistentFlags().String("author", "", "Project author (default: git user.name)")
+	newCmd.PersistentFlags().String("template-dir", "", "Directory with a user-defined template, or several to choose from with --template")
+	newCmd.PersistentFlags().StringToString("var", nil, "Value of a user-defined template variable as NAME=VALUE (repeatable)")
+	newCmd.PersistentFlags().String("license", "", "License to add: "+strings.Join(project.Licenses(), ", ")+" (default: none)")
+	pythonInstallCmd.Flags().Bool("default", false, "Make the installed interpreter the default")
+	setupCmd.PersistentFlags().Bool("allow-bootstrap", false, "Allow installing a package manager if none is available")
+	setupCmd.PersistentFlags().String("bootstrap-manager", "", "Package manager to bootstrap (choco, scoop, brew)")
+	setupCmd.PersistentFlags().String("bootstrap-script", "", "Path or URL of the package manager installer script")
+	setupCmd.PersistentFlags().String("bootstrap-sha256", "", "Pinned SHA-256 checksum of the installer script")
+	setupCmd.PersistentFlags().Bool("offline", false, "Install from a local package cache instead of the network")
+	setupCmd.PersistentFlags().String("cache", "", "Package cache directory created by 'devstation cache build'")
+	cacheBuildCmd.Flags().String("dir", "devstation-cache", "Directory to write the package cache to")
+	cacheBuildCmd.Flags().StringSlice("manifest", nil, "Requirements file declaring extra pip packages to cache (repeatable)")
+	upgradeCmd.Flags().Bool("check", false, "Only report outdated tools without upgrading them")
+	venvKernelCmd.Flags().String("name", "", "Kernel name (default: derived from the project directory)")
+	venvKernelCmd.Flags().String("display-name", "", "Name shown in Jupyter (default: \"Python (<project>)\")")
+	lintCmd.Flags().Bool("fix", false, "Fix problems in place where the linter supports it (ruff, black)")
+	addCmd.Flags().Bool("dev", false, "Add as a development dependency")
+	lockCmd.Flags().Bool("upgrade", false, "Upgrade all pinned packages to their latest allowed versions")
+	syncCmd.Flags().Bool("no-dev", false, "Sync only the runtime lock file, removing development dependencies")
+	testCmd.Flags().String("matrix", "", "Run the tests with each Python version of the project's noxfile.py/tox.ini, or the given versions (e.g. --matrix=3.11,3.12)")
+	testCmd.Flags().Lookup("matrix").NoOptDefVal = projectMatrix
+	publishCmd.Flags().String("repository", "", "Name of the configured repository to upload to")
+	publishCmd.MarkFlagRequired("repository")
+	testCmd.Flags().String("junit", "", "Write the results as a JUnit XML report to this file")
+	templatesCmd.PersistentFlags().String("template-dir", "", "Also look for templates in this directory, before the configured sources")
+	templatesShowCmd.Flags().String("type", "", "Only show templates of this type: python or c")
+	for _, c := range []*cobra.Command{venvCreateCmd, venvRecreateCmd} {
+		c.Flags().String("python", "", "Interpreter version (e.g. 3.11) or path to create the environment with")
+		c.Flags().Bool("no-install", false, "Do not install the project's dependencies")
+	}
+	
 	// Add all commands to root
 	rootCmd.AddCommand(setupCmd)
 	rootCmd.AddCommand(newCmd)
 	rootCmd.AddCommand(statusCmd)
+	rootCmd.AddCommand(upgradeCmd)
+	rootCmd.AddCommand(cacheCmd)
+	rootCmd.AddCommand(configCmd)
+	rootCmd.AddCommand(pythonInterpretersCmd)
+	rootCmd.AddCommand(venvCmd)
+	rootCmd.AddCommand(toolsCmd)
+	rootCmd.AddCommand(lockCmd)
+	rootCmd.AddCommand(syncCmd)
+	rootCmd.AddCommand(addCmd)
+	rootCmd.AddCommand(rmCmd)
+	rootCmd.AddCommand(lintCmd)
+	rootCmd.AddCommand(testCmd)
+	rootCmd.AddCommand(buildCmd)
+	rootCmd.AddCommand(publishCmd)
+	rootCmd.AddCommand(templatesCmd)
 }
@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"devstation-cli/pkg/config"
+)
+
+// configCmd groups the configuration commands
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "View and change devstation settings",
+	Long: `View and change devstation settings such as the pip package index, proxy and CA bundle.
+Settings are stored in a JSON file in the user config directory (override with DEVSTATION_CONFIG).`,
+}
+
+// configListCmd lists every configuration key and its value
+var configListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List all settings",
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := loadConfigOrExit()
+		for _, key := range cfg.AllKeys() {
+			value, _ := cfg.Get(key.Name)
+			fmt.Printf("%-24s %-40s # %s\n", key.Name, value, key.Description)
+		}
+	},
+}
+
+// configGetCmd prints the value of one configuration key
+var configGetCmd = &cobra.Command{
+	Use:   "get [key]",
+	Short: "Print a setting",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		value, err := loadConfigOrExit().Get(args[0])
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println(value)
+	},
+}
+
+// configSetCmd assigns a configuration key
+var configSetCmd = &cobra.Command{
+	Use:   "set [key] [value]",
+	Short: "Change a setting (comma-separate list values)",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := loadConfigOrExit()
+		if err := cfg.Set(args[0], args[1]); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		saveConfigOrExit(cfg)
+		fmt.Printf("✓ %s updated\n", args[0])
+	},
+}
+
+// configUnsetCmd clears a configuration key
+var configUnsetCmd = &cobra.Command{
+	Use:   "unset [key]",
+	Short: "Clear a setting",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := loadConfigOrExit()
+		if err := cfg.Unset(args[0]); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		saveConfigOrExit(cfg)
+		fmt.Printf("✓ %s cleared\n", args[0])
+	},
+}
+
+// configPathCmd prints the location of the configuration file
+var configPathCmd = &cobra.Command{
+	Use:   "path",
+	Short: "Print the location of the settings file",
+	Run: func(cmd *cobra.Command, args []string) {
+		path, err := config.Path()
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println(path)
+	},
+}
+
+// loadConfigOrExit loads the configuration file, exiting on error
+func loadConfigOrExit() *config.Config {
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+	return cfg
+}
+
+// saveConfigOrExit saves the configuration file, exiting on error
+func saveConfigOrExit(cfg *config.Config) {
+	if err := cfg.Save(); err != nil {
+		fmt.Printf("Error saving config: %v\n", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"devstation-cli/pkg/python"
+)
+
+// addCmd adds dependencies to the current Python project
+var addCmd = &cobra.Command{
+	Use:   "add requirement [requirement...]",
+	Short: "Add dependencies to the current Python project",
+	Long: `Declare dependencies such as "requests>=2.31" in the project's requirements files and
+pyproject.toml, install them into the project's virtual environment and refresh the lock files.
+Poetry, uv and Pipenv projects are updated with their own tool.`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		dev, _ := cmd.Flags().GetBool("dev")
+
+		projectDir, venv := currentProjectVenv()
+		pythonSetup := python.NewPythonSetup(nil)
+		for _, requirement := range args {
+			if err := pythonSetup.AddDependency(projectDir, venv, requirement, dev); err != nil {
+				fmt.Printf("Error adding %s: %v\n", requirement, err)
+				os.Exit(1)
+			}
+		}
+		fmt.Println("✓ Dependencies added")
+	},
+}
+
+// rmCmd removes dependencies from the current Python project
+var rmCmd = &cobra.Command{
+	Use:   "rm package [package...]",
+	Short: "Remove dependencies from the current Python project",
+	Long: `Remove dependencies from the project's requirements files and pyproject.toml, uninstall them
+from the project's virtual environment and refresh the lock files. Poetry, uv and Pipenv projects
+are updated with their own tool.`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		projectDir, venv := currentProjectVenv()
+		pythonSetup := python.NewPythonSetup(nil)
+		for _, name := range args {
+			if err := pythonSetup.RemoveDependency(projectDir, venv, name); err != nil {
+				fmt.Printf("Error removing %s: %v\n", name, err)
+				os.Exit(1)
+			}
+		}
+		fmt.Println("✓ Dependencies removed")
+	},
+}
@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"devstation-cli/pkg/python"
+)
+
+// lintCmd runs the project's configured linters
+var lintCmd = &cobra.Command{
+	Use:   "lint",
+	Short: "Run the project's linters and type checker in its virtual environment",
+	Long: `Run ruff, black, flake8 and mypy inside the project's virtual environment, each one only when the
+project has configuration for it, and print a pass/fail summary. Exits non-zero if any linter fails.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		fix, _ := cmd.Flags().GetBool("fix")
+
+		projectDir, venv := currentProjectVenv()
+		results, err := python.Lint(projectDir, venv, fix)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Println("\nLint summary:")
+		failed, ran := 0, 0
+		for _, result := range results {
+			switch {
+			case result.Skipped != "":
+				fmt.Printf("  - %-8s skipped: %s\n", result.Linter, result.Skipped)
+			case result.Passed:
+				ran++
+				fmt.Printf("  ✓ %-8s passed\n", result.Linter)
+			default:
+				ran++
+				failed++
+				fmt.Printf("  ✗ %-8s failed\n", result.Linter)
+			}
+		}
+
+		if ran == 0 {
+			fmt.Println("No linters ran")
+			os.Exit(1)
+		}
+		if failed > 0 {
+			fmt.Printf("%d of %d linter(s) failed\n", failed, ran)
+			os.Exit(1)
+		}
+		fmt.Printf("✓ All %d linter(s) passed\n", ran)
+	},
+}
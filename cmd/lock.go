@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"devstation-cli/pkg/python"
+)
+
+// lockCmd pins the project's dependencies into lock files
+var lockCmd = &cobra.Command{
+	Use:   "lock",
+	Short: "Pin the project's dependencies into hashed lock files",
+	Long: `Compile requirements.in and requirements-dev.in into requirements.txt and requirements-dev.txt
+with pinned versions and hashes using pip-compile. Poetry, uv and Pipenv projects are locked with
+their own tool.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		upgrade, _ := cmd.Flags().GetBool("upgrade")
+
+		projectDir, venv := currentProjectVenv()
+		if err := python.NewPythonSetup(nil).Lock(projectDir, venv, upgrade); err != nil {
+			fmt.Printf("Error locking dependencies: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println("✓ Lock files updated")
+	},
+}
+
+// syncCmd makes the project's venv match its lock files
+var syncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Make the project's virtual environment match its lock files exactly",
+	Long: `Install exactly the packages pinned in requirements.txt and requirements-dev.txt with pip-sync,
+removing anything else, then reinstall the project in editable mode. Poetry, uv and Pipenv projects
+are synced with their own tool.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		noDev, _ := cmd.Flags().GetBool("no-dev")
+
+		projectDir, venv := currentProjectVenv()
+		if err := python.NewPythonSetup(nil).Sync(projectDir, venv, !noDev); err != nil {
+			fmt.Printf("Error syncing virtual environment: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println("✓ Virtual environment matches the lock files")
+	},
+}
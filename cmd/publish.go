@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"devstation-cli/pkg/python"
+)
+
+// buildCmd builds the project's distributions
+var buildCmd = &cobra.Command{
+	Use:   "build",
+	Short: "Build the project's sdist and wheel and check their metadata",
+	Long: `Build the Python project's sdist and wheel with 'python -m build', which installs the build backend
+into an isolated environment, validate them with 'twine check --strict' and write them to dist/.
+build and twine are installed into the project's virtual environment if needed.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		projectDir, venv := currentProjectVenv()
+		files, err := python.NewPythonSetup(nil).Build(projectDir, venv)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		for _, file := range files {
+			fmt.Printf("✓ Built %s\n", file)
+		}
+	},
+}
+
+// publishCmd uploads the project's distributions to a configured repository
+var publishCmd = &cobra.Command{
+	Use:   "publish",
+	Short: "Upload the project's distributions to a configured package index",
+	Long: `Upload the wheels and sdists in dist/ to a repository configured with
+'devstation config set repository.NAME.url URL-or-directory'.
+
+An http(s) URL is uploaded to with twine (e.g. a pypiserver or a private index); the password is read
+from TWINE_PASSWORD or prompted for. A local directory or file:// URL is written as a simple index
+that pip can install from with --index-url file:///path/to/dir. Files the repository already has
+are skipped.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		repository, _ := cmd.Flags().GetString("repository")
+
+		projectDir, venv := currentProjectVenv()
+		files, err := python.NewPythonSetup(nil).Publish(projectDir, venv, repository)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		for _, file := range files {
+			fmt.Printf("✓ Published %s\n", file)
+		}
+		if len(files) == 0 {
+			fmt.Println("Nothing new to publish")
+		}
+	},
+}
@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"devstation-cli/pkg/config"
+	"devstation-cli/pkg/installer"
+	"devstation-cli/pkg/python"
+)
+
+// pythonInterpretersCmd groups the Python interpreter management commands
+var pythonInterpretersCmd = &cobra.Command{
+	Use:   "python",
+	Short: "Manage Python interpreters",
+	Long:  `Install side-by-side Python versions, list the interpreters devstation knows about and choose the default one.`,
+}
+
+// pythonListCmd lists the registered and discovered interpreters
+var pythonListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List registered and discovered Python interpreters",
+	Run: func(cmd *cobra.Command, args []string) {
+		pythonConfig := config.Current().Python
+
+		fmt.Println("Registered interpreters:")
+		if len(pythonConfig.Interpreters) == 0 {
+			fmt.Println("  none (run 'devstation python install VERSION' or 'devstation python use PATH')")
+		}
+		for _, interpreter := range pythonConfig.Interpreters {
+			marker := " "
+			if interpreter.Path == pythonConfig.Default {
+				marker = "*"
+			}
+			fmt.Printf("%s %-8s %s\n", marker, interpreter.Version, interpreter.Path)
+		}
+
+		fmt.Println("\nDiscovered interpreters:")
+		candidates := python.Discover()
+		if len(candidates) == 0 {
+			fmt.Println("  none")
+		}
+		for _, candidate := range candidates {
+			fmt.Printf("  %-8s %-6s %-4s %-5s %-12s %s\n",
+				candidate.Version, candidate.Arch, yesNo(candidate.HasPip, "pip"), yesNo(candidate.HasVenv, "venv"),
+				"("+candidate.Source+")", candidate.Path)
+		}
+	},
+}
+
+// pythonFindCmd shows which interpreter best matches a version constraint
+var pythonFindCmd = &cobra.Command{
+	Use:   "find [constraint]",
+	Short: "Show the interpreter that best matches a version constraint",
+	Long:  `Show the discovered interpreter that best matches a version constraint such as 3.11 or ">=3.9,<3.13".`,
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		spec := ""
+		if len(args) > 0 {
+			spec = args[0]
+		}
+
+		candidate, err := python.BestMatch(spec)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("%s (Python %s, %s, found via %s)\n", candidate.Path, candidate.Version, candidate.Arch, candidate.Source)
+	},
+}
+
+// yesNo renders a feature flag for the interpreter listing
+func yesNo(ok bool, feature string) string {
+	if ok {
+		return feature
+	}
+	return "-"
+}
+
+// pythonInstallCmd installs a specific Python version
+var pythonInstallCmd = &cobra.Command{
+	Use:   "install [version]",
+	Short: "Install a Python version side by side with others",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		makeDefault, _ := cmd.Flags().GetBool("default")
+
+		pm := installer.GetAvailablePackageManager()
+		if pm == nil {
+			fmt.Println("No package manager available. Please install Chocolatey or winget first.")
+			os.Exit(1)
+		}
+
+		interpreter, err := python.NewPythonSetup(pm).InstallVersion(args[0])
+		if err != nil {
+			fmt.Printf("Error installing Python %s: %v\n", args[0], err)
+			os.Exit(1)
+		}
+		if makeDefault {
+			if err := python.RegisterInterpreter(interpreter, true); err != nil {
+				fmt.Printf("Error setting default interpreter: %v\n", err)
+				os.Exit(1)
+			}
+		}
+
+		fmt.Printf("✓ Python %s registered at %s\n", interpreter.Version, interpreter.Path)
+	},
+}
+
+// pythonUseCmd selects the default interpreter
+var pythonUseCmd = &cobra.Command{
+	Use:   "use [version|path]",
+	Short: "Set the default Python interpreter",
+	Long:  `Set the interpreter devstation uses for pip, virtual environments and projects, by version (e.g. 3.11) or by path.`,
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		interpreter, err := resolveInterpreter(args[0])
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if err := python.RegisterInterpreter(interpreter, true); err != nil {
+			fmt.Printf("Error setting default interpreter: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("✓ Default Python is now %s (%s)\n", interpreter.Version, interpreter.Path)
+	},
+}
+
+// resolveInterpreter finds an interpreter from a version number or an executable path
+func resolveInterpreter(versionOrPath string) (config.Interpreter, error) {
+	if strings.ContainsAny(versionOrPath, `/\`) {
+		interpreter, err := python.QueryInterpreter(versionOrPath)
+		if err != nil {
+			return config.Interpreter{}, fmt.Errorf("%s is not a working Python interpreter: %v", versionOrPath, err)
+		}
+		return interpreter, nil
+	}
+	return python.FindInterpreter(versionOrPath)
+}
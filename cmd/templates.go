@@ -0,0 +1,271 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"devstation-cli/pkg/cdev"
+	"devstation-cli/pkg/project"
+	"devstation-cli/pkg/python"
+)
+
+// exampleProject is the project name templates are rendered with by show and validate
+const exampleProject = "example-project"
+
+// templatesCmd groups the commands that inspect project templates
+var templatesCmd = &cobra.Command{
+	Use:   "templates",
+	Short: "List, show and validate project templates",
+	Long: `Inspect the built-in project templates and user-defined ones.
+
+A user-defined template is a directory with a template.json manifest and .tmpl files, which are
+rendered on top of the built-in scaffold of its type. Templates are found in the directories and
+git repositories listed in 'devstation config set templates.sources DIR,git+PATH', or passed to
+'devstation new' with --template-dir.`,
+}
+
+// templatesListCmd lists the available templates
+var templatesListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List built-in and user-defined templates",
+	Run: func(cmd *cobra.Command, args []string) {
+		entries, errs := templateEntries(templateSources(cmd))
+		for _, err := range errs {
+			fmt.Printf("Warning: %v\n", err)
+		}
+
+		seen := make(map[string]bool)
+		for _, entry := range entries {
+			key := entry.Kind + "/" + entry.Name
+			source := entry.Source
+			if seen[key] {
+				source += ", shadowed"
+			}
+			seen[key] = true
+			fmt.Printf("  %-14s %-7s %s (%s)\n", entry.Name, entry.Kind, entry.Description, source)
+		}
+	},
+}
+
+// templatesShowCmd shows a template's details and the files it generates
+var templatesShowCmd = &cobra.Command{
+	Use:   "show [name]",
+	Short: "Show a template's variables and the files it generates",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		kind, _ := cmd.Flags().GetString("type")
+		entries, errs := templateEntries(templateSources(cmd))
+		for _, err := range errs {
+			fmt.Printf("Warning: %v\n", err)
+		}
+
+		found := false
+		for _, entry := range entries {
+			if entry.Name != args[0] || (kind != "" && entry.Kind != kind) {
+				continue
+			}
+			if found {
+				fmt.Println()
+			}
+			found = true
+			showTemplate(entry)
+		}
+		if !found {
+			fmt.Printf("Error: unknown template %q; see 'devstation templates list'\n", args[0])
+			os.Exit(1)
+		}
+	},
+}
+
+// templatesValidateCmd checks user-defined templates
+var templatesValidateCmd = &cobra.Command{
+	Use:   "validate [name|path...]",
+	Short: "Check user-defined templates by rendering an example project",
+	Long: `Check the manifest of user-defined templates and render an example project from each with
+the same code 'devstation new' uses, reporting invalid manifests, template syntax errors and
+references to undeclared variables. Arguments are template names or template directories;
+without arguments every configured template is checked.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		var entries []templateEntry
+		var errs []error
+		if len(args) == 0 {
+			entries, errs = userTemplateEntries(templateSources(cmd))
+		}
+		for _, arg := range args {
+			if info, err := os.Stat(arg); err == nil && info.IsDir() {
+				found, sourceErrs := userTemplateEntries([]string{arg})
+				if len(found) == 0 && len(sourceErrs) == 0 {
+					sourceErrs = append(sourceErrs, fmt.Errorf("no %s in %s or its subdirectories", project.ManifestFile, arg))
+				}
+				entries, errs = append(entries, found...), append(errs, sourceErrs...)
+				continue
+			}
+
+			all, sourceErrs := templateEntries(templateSources(cmd))
+			errs = append(errs, sourceErrs...)
+			matched := false
+			for _, entry := range all {
+				if entry.Name == arg {
+					entries, matched = append(entries, entry), true
+				}
+			}
+			if !matched {
+				errs = append(errs, fmt.Errorf("unknown template %q", arg))
+			}
+		}
+
+		failed := len(errs)
+		for _, err := range errs {
+			fmt.Printf("  ✗ %v\n", err)
+		}
+		for _, entry := range entries {
+			if _, err := renderExample(entry); err != nil {
+				fmt.Printf("  ✗ %s (%s, %s): %v\n", entry.Name, entry.Kind, entry.Source, err)
+				failed++
+				continue
+			}
+			fmt.Printf("  ✓ %s (%s, %s)\n", entry.Name, entry.Kind, entry.Source)
+			if entry.User != nil {
+				unused, err := entry.User.UnusedVariables()
+				if err != nil {
+					fmt.Printf("    Warning: %v\n", err)
+				}
+				for _, name := range unused {
+					fmt.Printf("    Warning: variable %s is never used\n", name)
+				}
+			}
+		}
+
+		if len(entries) == 0 && failed == 0 {
+			fmt.Println("No user-defined templates configured (see 'devstation templates --help')")
+		}
+		if failed > 0 {
+			os.Exit(1)
+		}
+	},
+}
+
+// templateEntry is a built-in or user-defined template
+type templateEntry struct {
+	Name        string
+	Kind        string // project.KindPython or project.KindC
+	Description string
+	Source      string
+	User        *project.Template // nil for built-in templates
+}
+
+// templateSources returns the --template-dir of the templates commands followed by the
+// configured template sources
+func templateSources(cmd *cobra.Command) []string {
+	sources := project.TemplateSources()
+	if dir, _ := cmd.Flags().GetString("template-dir"); dir != "" {
+		sources = append([]string{dir}, sources...)
+	}
+	return sources
+}
+
+// templateEntries lists the built-in templates followed by the user-defined templates of
+// sources, in the order 'devstation new' looks them up
+func templateEntries(sources []string) ([]templateEntry, []error) {
+	var entries []templateEntry
+	for _, name := range python.TemplateNames() {
+		entries = append(entries, templateEntry{Name: name, Kind: project.KindPython, Description: python.ProjectTemplates[name].Description, Source: "built-in"})
+	}
+	entries = append(entries, templateEntry{Name: cdev.DefaultTemplate, Kind: project.KindC, Description: cdev.DefaultTemplateDescription, Source: "built-in"})
+
+	user, errs := userTemplateEntries(sources)
+	return append(entries, user...), errs
+}
+
+// userTemplateEntries lists the user-defined templates of sources
+func userTemplateEntries(sources []string) ([]templateEntry, []error) {
+	templates, errs := project.FindTemplates(sources)
+	var entries []templateEntry
+	for i := range templates {
+		t := &templates[i]
+		entries = append(entries, templateEntry{Name: t.Name, Kind: t.Type, Description: t.Description, Source: t.Source, User: t})
+	}
+	return entries, errs
+}
+
+// renderExample renders a template into an example project with the scaffolding code of its
+// type, without writing any files. Variables take their example values.
+func renderExample(entry templateEntry) (map[string]string, error) {
+	opts := project.Options{Author: "Example Author", UserTemplate: entry.User}
+	if entry.User != nil {
+		opts.Vars = project.ExampleVars(entry.User.Variables)
+	}
+	if entry.Kind == project.KindC {
+		return cdev.RenderProject(exampleProject, opts)
+	}
+	return python.NewPythonSetup(nil).RenderProject(exampleProject, python.ProjectOptions{Template: entry.Name, Options: opts})
+}
+
+// showTemplate prints a template's details and the files of an example project
+func showTemplate(entry templateEntry) {
+	fmt.Printf("%s (%s template, %s)\n", entry.Name, entry.Kind, entry.Source)
+	if entry.Description != "" {
+		fmt.Printf("  %s\n", entry.Description)
+	}
+
+	if user := entry.User; user != nil {
+		fmt.Printf("  Directory: %s\n", user.Dir)
+		if len(user.Variables) > 0 {
+			fmt.Println("\nVariables (set with --var NAME=VALUE):")
+			for _, v := range user.Variables {
+				var details []string
+				if v.Prompt != "" {
+					details = append(details, v.Prompt)
+				}
+				if len(v.Choices) > 0 {
+					details = append(details, "one of "+strings.Join(v.Choices, ", "))
+				}
+				if v.Default != "" {
+					details = append(details, "default "+v.Default)
+				}
+				if v.Required {
+					details = append(details, "required")
+				}
+				fmt.Println(strings.TrimRight(fmt.Sprintf("  %-14s %s", v.Name, strings.Join(details, "; ")), " "))
+			}
+		}
+		if len(user.Dependencies) > 0 {
+			fmt.Printf("\nDependencies: %s\n", strings.Join(user.Dependencies, ", "))
+		}
+		if len(user.DevDependencies) > 0 {
+			fmt.Printf("Dev dependencies: %s\n", strings.Join(user.DevDependencies, ", "))
+		}
+	} else if entry.Kind == project.KindPython {
+		template := python.ProjectTemplates[entry.Name]
+		if len(template.Dependencies) > 0 {
+			fmt.Printf("\nDependencies: %s\n", strings.Join(template.Dependencies, ", "))
+		}
+		if len(template.DevDependencies) > 0 {
+			fmt.Printf("Dev dependencies: %s\n", strings.Join(template.DevDependencies, ", "))
+		}
+	}
+
+	files, err := renderExample(entry)
+	if err != nil {
+		fmt.Printf("\n✗ The template does not render: %v\n", err)
+		return
+	}
+	var paths []string
+	for path := range files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	fmt.Printf("\nFiles of a new project named %s:\n", exampleProject)
+	for _, path := range paths {
+		fmt.Printf("  %s\n", path)
+	}
+}
+
+// stdinIsTerminal reports whether the user can be prompted for input
+func stdinIsTerminal() bool {
+	info, err := os.Stdin.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
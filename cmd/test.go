@@ -0,0 +1,191 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"devstation-cli/pkg/cdev"
+	"devstation-cli/pkg/python"
+	"devstation-cli/pkg/testrun"
+)
+
+// projectMatrix is the --matrix value meaning "the versions declared by the project"
+const projectMatrix = "project"
+
+// testCmd runs the tests of the Python or C project around the working directory
+var testCmd = &cobra.Command{
+	Use:   "test [-- pytest-args...]",
+	Short: "Run the project's tests",
+	Long: `Detect the project around the current directory and run its tests:
+
+  Python  pytest inside the project's virtual environment; arguments after -- are passed to pytest
+  C       configure and build with CMake and run CTest, or 'make test' when cmake is not installed
+          or the project only has a Makefile
+
+A summary of passed, failed and skipped tests is printed for every project type, and --junit writes
+it as a JUnit XML report for CI servers.
+
+With --matrix the Python tests run once per Python version of the project's noxfile.py or tox.ini
+(or the versions given, e.g. --matrix=3.11,3.12), each in its own environment under
+.devstation/matrix created with the installed interpreter for that version. Versions with no
+installed interpreter are reported as missing; install them with 'devstation python install <version>'.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		matrix, _ := cmd.Flags().GetString("matrix")
+		junit, _ := cmd.Flags().GetString("junit")
+
+		kind, projectDir := detectTestProject()
+		if kind != "python" && matrix != "" {
+			fmt.Println("Error: --matrix is only supported for Python projects")
+			os.Exit(1)
+		}
+		if kind == "c" && len(args) > 0 {
+			fmt.Println("Error: extra test arguments are only supported for Python projects")
+			os.Exit(1)
+		}
+
+		var suites []testrun.Suite
+		var missing []string
+		switch {
+		case kind == "c":
+			suite, err := cdev.RunTests(projectDir)
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+				os.Exit(1)
+			}
+			suites = append(suites, suite)
+		case matrix == "":
+			venv, err := python.ProjectVenv(projectDir)
+			if err != nil {
+				fmt.Printf("Error locating virtual environment: %v\n", err)
+				os.Exit(1)
+			}
+			suite, err := python.RunTests(projectDir, venv, args)
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+				os.Exit(1)
+			}
+			suites = append(suites, suite)
+		default:
+			versions, err := matrixVersions(projectDir, matrix)
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+				os.Exit(1)
+			}
+			for _, result := range python.NewPythonSetup(nil).RunMatrix(projectDir, versions, args) {
+				if result.Missing() {
+					missing = append(missing, result.Version)
+					continue
+				}
+				suites = append(suites, result.Suite)
+			}
+		}
+
+		if junit != "" {
+			if err := testrun.WriteJUnit(junit, suites); err != nil {
+				fmt.Printf("Error: %v\n", err)
+				os.Exit(1)
+			}
+		}
+
+		if !printTestSummary(suites, missing) {
+			os.Exit(1)
+		}
+		if junit != "" {
+			fmt.Printf("JUnit report written to %s\n", junit)
+		}
+	},
+}
+
+// detectTestProject finds the nearest Python or C project around the working directory.
+// A directory that is both, such as a C extension package, is tested as a Python project.
+func detectTestProject() (string, string) {
+	pythonDir, pythonErr := python.FindProject(".")
+	cDir, cErr := cdev.FindProject(".")
+	switch {
+	case pythonErr == nil && (cErr != nil || len(pythonDir) >= len(cDir)):
+		return "python", pythonDir
+	case cErr == nil:
+		return "c", cDir
+	}
+	fmt.Println("Error: no Python or C project found in the current directory or its parents")
+	os.Exit(1)
+	return "", ""
+}
+
+// matrixVersions returns the versions selected by --matrix: the project's own or an explicit list
+func matrixVersions(projectDir, matrix string) ([]string, error) {
+	if matrix == projectMatrix {
+		return python.ProjectMatrix(projectDir)
+	}
+	return python.ParseMatrix(strings.Split(matrix, ","))
+}
+
+// printTestSummary prints one line per test run plus the failing tests, and reports whether
+// every run passed. Matrix versions without an interpreter are listed as missing.
+func printTestSummary(suites []testrun.Suite, missing []string) bool {
+	fmt.Println("\nTest summary:")
+	failedRuns := 0
+	for _, suite := range suites {
+		passed, failed, skipped := suite.Counts()
+		counts := fmt.Sprintf("%d passed", passed)
+		if failed > 0 {
+			counts += fmt.Sprintf(", %d failed", failed)
+		}
+		if skipped > 0 {
+			counts += fmt.Sprintf(", %d skipped", skipped)
+		}
+		counts += fmt.Sprintf(" (%s)", suite.Duration.Round(10*time.Millisecond))
+
+		if suite.Passed() {
+			fmt.Printf("  ✓ %-8s %s\n", suite.Name, counts)
+			continue
+		}
+		failedRuns++
+		fmt.Printf("  ✗ %-8s %s\n", suite.Name, counts)
+		if suite.Err != nil {
+			fmt.Printf("      error: %v\n", suite.Err)
+		}
+		for _, c := range suite.Cases {
+			if c.Failure != "" && !c.Skipped {
+				fmt.Printf("      ✗ %s: %s\n", testName(c), firstLine(c.Failure))
+			}
+		}
+	}
+	for _, version := range missing {
+		fmt.Printf("  - py%-6s missing: no interpreter found\n", version)
+	}
+
+	if len(missing) > 0 {
+		fmt.Printf("%d version(s) missing; install them with 'devstation python install <version>'\n", len(missing))
+	}
+	if len(suites) == 0 {
+		fmt.Println("No tests could be run")
+		return false
+	}
+	if failedRuns > 0 {
+		fmt.Printf("%d of %d test run(s) failed\n", failedRuns, len(suites))
+		return false
+	}
+	fmt.Printf("✓ All %d test run(s) passed\n", len(suites))
+	return true
+}
+
+// testName qualifies a test case with its class or module when it has one
+func testName(c testrun.Case) string {
+	if c.Classname == "" {
+		return c.Name
+	}
+	return c.Classname + "." + c.Name
+}
+
+// firstLine shortens a failure message to its first line for the summary
+func firstLine(s string) string {
+	s = strings.TrimSpace(s)
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		return s[:i]
+	}
+	return s
+}
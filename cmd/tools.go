@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"devstation-cli/pkg/python"
+)
+
+// toolsCmd groups the commands that manage isolated Python tool environments
+var toolsCmd = &cobra.Command{
+	Use:   "tools",
+	Short: "Manage Python command-line tools in isolated environments",
+	Long: `Install Python command-line applications such as black or pytest into their own virtual
+environments, with shims in a single directory on PATH, so they never touch the system interpreter.`,
+}
+
+// toolsListCmd lists the installed tools
+var toolsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List installed tools and their commands",
+	Run: func(cmd *cobra.Command, args []string) {
+		tools, err := python.InstalledTools()
+		if err != nil {
+			fmt.Printf("Error listing tools: %v\n", err)
+			os.Exit(1)
+		}
+
+		if len(tools) == 0 {
+			fmt.Println("No tools installed (run 'devstation setup python' or 'devstation tools install NAME')")
+			return
+		}
+		for _, tool := range tools {
+			fmt.Printf("  %-12s %-10s %s\n", tool.Name, tool.Version, strings.Join(tool.Commands, ", "))
+		}
+
+		shimDir, _ := python.ShimDir()
+		fmt.Printf("\nShims: %s\n", shimDir)
+		if !python.ShimDirOnPath() {
+			fmt.Println("Warning: the shim directory is not on PATH")
+		}
+	},
+}
+
+// toolsInstallCmd installs tools into isolated environments
+var toolsInstallCmd = &cobra.Command{
+	Use:   "install [tool...]",
+	Short: "Install tools into isolated environments (default: the standard tool set)",
+	Long: `Install tools into isolated environments, one per package. A tool may carry a version
+specifier such as "black==24.1", which is passed to pip; the environment is named after the package.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		names := args
+		if len(names) == 0 {
+			names = python.GlobalTools
+		}
+
+		pythonSetup := python.NewPythonSetup(nil)
+		failed := 0
+		for _, name := range names {
+			fmt.Printf("Installing %s...\n", name)
+			if err := pythonSetup.InstallTool(name); err != nil {
+				fmt.Printf("Error installing %s: %v\n", name, err)
+				failed++
+				continue
+			}
+			fmt.Printf("✓ %s installed\n", name)
+		}
+
+		if !python.ShimDirOnPath() {
+			shimDir, _ := python.ShimDir()
+			fmt.Printf("Add %s to your PATH to use the installed tools\n", shimDir)
+		}
+		if failed > 0 {
+			os.Exit(1)
+		}
+	},
+}
+
+// toolsUninstallCmd removes tools and their shims
+var toolsUninstallCmd = &cobra.Command{
+	Use:   "uninstall [tool...]",
+	Short: "Remove tools and their shims",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		for _, name := range args {
+			if err := python.UninstallTool(name); err != nil {
+				fmt.Printf("Error uninstalling %s: %v\n", name, err)
+				os.Exit(1)
+			}
+			fmt.Printf("✓ %s uninstalled\n", name)
+		}
+	},
+}
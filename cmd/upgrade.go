@@ -0,0 +1,174 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"devstation-cli/pkg/cdev"
+	"devstation-cli/pkg/installer"
+	"devstation-cli/pkg/python"
+)
+
+// upgradeCmd upgrades the tools devstation manages
+var upgradeCmd = &cobra.Command{
+	Use:   "upgrade [tool...]",
+	Short: "Upgrade managed development tools",
+	Long: `List outdated tools installed by devstation (system packages and isolated Python tools),
+show current and available versions, and upgrade the selected tools or all of them.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		checkOnly, _ := cmd.Flags().GetBool("check")
+		if err := upgradeTools(args, checkOnly); err != nil {
+			fmt.Printf("Error upgrading tools: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+// managedTool is an outdated tool together with the means to upgrade it
+type managedTool struct {
+	installer.PackageVersion
+	Source  string
+	Upgrade func() error
+}
+
+// managedSystemPackages returns the system packages installed by the setup commands
+func managedSystemPackages() []string {
+	var packages []string
+	seen := make(map[string]bool)
+
+	groups := [][]string{{"python"}, python.DevelopmentTools, cdev.CompilerPackages, cdev.DevelopmentTools}
+	for _, group := range groups {
+		for _, pkg := range group {
+			if !seen[pkg] {
+				seen[pkg] = true
+				packages = append(packages, pkg)
+			}
+		}
+	}
+
+	return packages
+}
+
+// findOutdatedTools collects the outdated managed tools from the package manager and the Python tool environments
+func findOutdatedTools(pm installer.PackageManager) ([]managedTool, error) {
+	var tools []managedTool
+
+	systemVersions, err := pm.Outdated()
+	if err != nil {
+		return nil, err
+	}
+	for _, pkg := range managedSystemPackages() {
+		for _, version := range systemVersions {
+			if !installer.MatchesPackage(version.Name, pkg) {
+				continue
+			}
+			name := pkg
+			version.Name = pkg
+			tools = append(tools, managedTool{
+				PackageVersion: version,
+				Source:         "system",
+				Upgrade:        func() error { return pm.Update(name) },
+			})
+			break
+		}
+	}
+
+	pythonSetup := python.NewPythonSetup(pm)
+	pipVersions, err := pythonSetup.OutdatedTools()
+	if err != nil {
+		fmt.Printf("Warning: Could not check Python tools: %v\n", err)
+	}
+	for _, version := range pipVersions {
+		name := version.Name
+		tools = append(tools, managedTool{
+			PackageVersion: version,
+			Source:         "tool",
+			Upgrade:        func() error { return pythonSetup.UpgradeTool(name) },
+		})
+	}
+
+	return tools, nil
+}
+
+// selectTools filters the outdated tools down to the names requested on the command line
+func selectTools(tools []managedTool, names []string) ([]managedTool, error) {
+	if len(names) == 0 {
+		return tools, nil
+	}
+
+	managed := make(map[string]bool)
+	for _, pkg := range managedSystemPackages() {
+		managed[pkg] = true
+	}
+	for _, pkg := range python.GlobalTools {
+		managed[python.NormalizePackageName(pkg)] = true
+	}
+	installedTools, _ := python.InstalledTools()
+	for _, tool := range installedTools {
+		managed[tool.Name] = true
+	}
+
+	var selected []managedTool
+	for _, name := range names {
+		if !managed[name] && !managed[python.NormalizePackageName(name)] {
+			return nil, fmt.Errorf("%s is not a tool managed by devstation", name)
+		}
+		for _, tool := range tools {
+			if tool.Name == name || tool.Name == python.NormalizePackageName(name) {
+				selected = append(selected, tool)
+			}
+		}
+	}
+
+	return selected, nil
+}
+
+// upgradeTools reports outdated managed tools and upgrades them unless checkOnly is set
+func upgradeTools(names []string, checkOnly bool) error {
+	pm := installer.GetAvailablePackageManager()
+	if pm == nil {
+		return fmt.Errorf("no package manager available")
+	}
+
+	fmt.Println("Checking for outdated tools...")
+	tools, err := findOutdatedTools(pm)
+	if err != nil {
+		return err
+	}
+
+	tools, err = selectTools(tools, names)
+	if err != nil {
+		return err
+	}
+
+	if len(tools) == 0 {
+		fmt.Println("✓ All managed tools are up to date")
+		return nil
+	}
+
+	fmt.Printf("\n%-28s %-8s %-16s %-16s\n", "Tool", "Source", "Current", "Available")
+	for _, tool := range tools {
+		fmt.Printf("%-28s %-8s %-16s %-16s\n", tool.Name, tool.Source, tool.Current, tool.Available)
+	}
+
+	if checkOnly {
+		return nil
+	}
+
+	fmt.Println()
+	var failed []string
+	for _, tool := range tools {
+		if err := tool.Upgrade(); err != nil {
+			fmt.Printf("Warning: Failed to upgrade %s: %v\n", tool.Name, err)
+			failed = append(failed, tool.Name)
+		}
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("failed to upgrade %d tool(s): %v", len(failed), failed)
+	}
+
+	fmt.Println("✓ Upgrade complete!")
+	return nil
+}
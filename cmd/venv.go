@@ -0,0 +1,252 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+
+	"github.com/spf13/cobra"
+	"devstation-cli/pkg/python"
+)
+
+// venvCmd groups the commands that manage the current project's virtual environment
+var venvCmd = &cobra.Command{
+	Use:   "venv",
+	Short: "Manage the current project's virtual environment",
+	Long: `Create, recreate, inspect and delete the virtual environment of the Python project in the
+current directory (or the nearest parent containing pyproject.toml, requirements.txt or setup.py),
+and run commands inside it without activating it.`,
+}
+
+// venvCreateCmd creates the project's venv and installs the project into it
+var venvCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Create the project's virtual environment and install its dependencies",
+	Run: func(cmd *cobra.Command, args []string) {
+		projectDir, venv := currentProjectVenv()
+		if venv.Exists() {
+			fmt.Printf("Virtual environment already exists at %s (use 'devstation venv recreate' to rebuild it)\n", venv.Dir)
+			os.Exit(1)
+		}
+		createProjectVenv(cmd, projectDir, venv)
+	},
+}
+
+// venvRecreateCmd deletes and recreates the project's venv, optionally with another interpreter
+var venvRecreateCmd = &cobra.Command{
+	Use:   "recreate",
+	Short: "Delete and recreate the project's virtual environment",
+	Run: func(cmd *cobra.Command, args []string) {
+		projectDir, venv := currentProjectVenv()
+		if venv.Exists() {
+			fmt.Printf("Deleting %s...\n", venv.Dir)
+			if err := venv.Delete(); err != nil {
+				fmt.Printf("Error deleting virtual environment: %v\n", err)
+				os.Exit(1)
+			}
+		}
+		createProjectVenv(cmd, projectDir, venv)
+	},
+}
+
+// venvDeleteCmd removes the project's venv
+var venvDeleteCmd = &cobra.Command{
+	Use:   "delete",
+	Short: "Delete the project's virtual environment",
+	Run: func(cmd *cobra.Command, args []string) {
+		_, venv := currentProjectVenv()
+		if !venv.Exists() {
+			fmt.Printf("No virtual environment at %s\n", venv.Dir)
+			return
+		}
+		if err := venv.Delete(); err != nil {
+			fmt.Printf("Error deleting virtual environment: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("✓ Deleted %s\n", venv.Dir)
+	},
+}
+
+// venvInfoCmd shows the project's venv location, Python version and installed packages
+var venvInfoCmd = &cobra.Command{
+	Use:   "info",
+	Short: "Show the project's virtual environment and installed packages",
+	Run: func(cmd *cobra.Command, args []string) {
+		projectDir, venv := currentProjectVenv()
+		fmt.Printf("Project:     %s\n", projectDir)
+		fmt.Printf("Environment: %s\n", venv.Dir)
+		if !venv.Exists() {
+			fmt.Println("Status:      not created (run 'devstation venv create')")
+			return
+		}
+
+		interpreter, err := python.QueryInterpreter(venv.Python())
+		if err != nil {
+			fmt.Printf("Error: the virtual environment's interpreter does not work: %v\n", err)
+			fmt.Println("Run 'devstation venv recreate' to rebuild it.")
+			os.Exit(1)
+		}
+		fmt.Printf("Python:      %s (%s)\n", interpreter.Version, interpreter.Path)
+		fmt.Printf("Activate:    %s\n", venv.ActivateCommand(python.DetectShell()))
+
+		packages, err := venv.InstalledPackages()
+		if err != nil {
+			fmt.Printf("Error listing packages: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("\nInstalled packages (%d):\n", len(packages))
+		for _, pkg := range packages {
+			fmt.Printf("  %s\n", pkg)
+		}
+	},
+}
+
+// venvExecCmd runs a command inside the project's venv
+var venvExecCmd = &cobra.Command{
+	Use:   "exec -- command [args...]",
+	Short: "Run a command inside the project's virtual environment",
+	Long:  `Run a command with the project's virtual environment first on PATH, without activating it, e.g. 'devstation venv exec -- pytest -q'.`,
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		_, venv := currentProjectVenv()
+		if !venv.Exists() {
+			fmt.Printf("No virtual environment at %s (run 'devstation venv create')\n", venv.Dir)
+			os.Exit(1)
+		}
+
+		command := venv.Command(args[0], args[1:]...)
+		command.Stdin = os.Stdin
+		command.Stdout = os.Stdout
+		command.Stderr = os.Stderr
+		if err := command.Run(); err != nil {
+			var exitErr *exec.ExitError
+			if errors.As(err, &exitErr) {
+				os.Exit(exitErr.ExitCode())
+			}
+			fmt.Printf("Error running %s: %v\n", args[0], err)
+			os.Exit(1)
+		}
+	},
+}
+
+// venvKernelCmd registers a Jupyter kernel for the project's venv
+var venvKernelCmd = &cobra.Command{
+	Use:   "kernel",
+	Short: "Register a Jupyter kernel for the project's virtual environment",
+	Long: `Install ipykernel into the project's virtual environment and register a user kernel that runs it,
+so Jupyter installed elsewhere (e.g. 'devstation tools install jupyterlab') can use the project's packages.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		projectDir, venv := currentProjectVenv()
+
+		name, _ := cmd.Flags().GetString("name")
+		if name == "" {
+			name = python.KernelName(projectDir)
+		}
+		displayName, _ := cmd.Flags().GetString("display-name")
+		if displayName == "" {
+			displayName = python.KernelDisplayName(projectDir)
+		}
+
+		if err := python.NewPythonSetup(nil).RegisterKernel(venv, name, displayName); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("✓ Kernel %q registered as %s\n", displayName, name)
+	},
+}
+
+// venvKernelListCmd lists the registered Jupyter kernels
+var venvKernelListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List registered Jupyter kernels and flag stale ones",
+	Run: func(cmd *cobra.Command, args []string) {
+		kernels, err := python.Kernels()
+		if err != nil {
+			fmt.Printf("Error listing kernels: %v\n", err)
+			os.Exit(1)
+		}
+		if len(kernels) == 0 {
+			fmt.Println("No kernels registered")
+			return
+		}
+
+		stale := 0
+		for _, kernel := range kernels {
+			status := ""
+			if kernel.Stale {
+				status = "  (stale: interpreter missing)"
+				stale++
+			}
+			fmt.Printf("  %-24s %-28s %s%s\n", kernel.Name, kernel.DisplayName, kernel.Python, status)
+		}
+		if stale > 0 {
+			fmt.Printf("\n%d stale kernel(s); run 'devstation venv kernel prune' to remove them\n", stale)
+		}
+	},
+}
+
+// venvKernelPruneCmd removes kernels whose venvs were deleted
+var venvKernelPruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Remove Jupyter kernels whose interpreter no longer exists",
+	Run: func(cmd *cobra.Command, args []string) {
+		removed, err := python.PruneKernels()
+		for _, kernel := range removed {
+			fmt.Printf("✓ Removed %s (%s)\n", kernel.Name, kernel.Python)
+		}
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		if len(removed) == 0 {
+			fmt.Println("No stale kernels")
+		}
+	},
+}
+
+// currentProjectVenv locates the project around the working directory and its venv
+func currentProjectVenv() (string, python.Venv) {
+	projectDir, err := python.FindProject(".")
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	venv, err := python.ProjectVenv(projectDir)
+	if err != nil {
+		fmt.Printf("Error locating virtual environment: %v\n", err)
+		os.Exit(1)
+	}
+	return projectDir, venv
+}
+
+// createProjectVenv creates the venv with the interpreter selected by --python and
+// installs the project's dependencies unless --no-install is given
+func createProjectVenv(cmd *cobra.Command, projectDir string, venv python.Venv) {
+	pythonSetup := python.NewPythonSetup(nil)
+	if versionOrPath, _ := cmd.Flags().GetString("python"); versionOrPath != "" {
+		interpreter, err := resolveInterpreter(versionOrPath)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		pythonSetup.Interpreter = interpreter.Path
+	}
+
+	fmt.Printf("Creating virtual environment at %s with %s...\n", venv.Dir, pythonSetup.Python())
+	if err := pythonSetup.CreateVenv(venv); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if noInstall, _ := cmd.Flags().GetBool("no-install"); !noInstall {
+		if err := pythonSetup.InstallProject(venv, projectDir); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	fmt.Printf("✓ Virtual environment ready at %s\n", venv.Dir)
+	python.PrintActivation(projectDir, venv)
+}
@@ -0,0 +1,244 @@
+package cache
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"devstation-cli/pkg/installer"
+	"devstation-cli/pkg/python"
+)
+
+const (
+	// ManifestFile records what a cache directory contains
+	ManifestFile = "cache.json"
+	// WheelDir is the wheelhouse holding pip packages
+	WheelDir = "wheels"
+	// SystemDir holds system package artifacts
+	SystemDir = "system"
+)
+
+// Manifest describes the contents of a local package cache
+type Manifest struct {
+	Created time.Time        `json:"created"`
+	Manager string           `json:"manager"`
+	Python  []string         `json:"python"`
+	System  []SystemArtifact `json:"system"`
+}
+
+// SystemArtifact records a system package and the cached file it can be installed from
+type SystemArtifact struct {
+	Name     string `json:"name"`
+	Artifact string `json:"artifact,omitempty"` // Relative to the cache directory; empty if not downloadable
+}
+
+// Load reads the manifest of a cache directory
+func Load(dir string) (*Manifest, error) {
+	data, err := os.ReadFile(filepath.Join(dir, ManifestFile))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cache manifest: %v", err)
+	}
+
+	var manifest Manifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse cache manifest: %v", err)
+	}
+	return &manifest, nil
+}
+
+// Save writes the manifest into a cache directory
+func (m *Manifest) Save(dir string) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, ManifestFile), data, 0644)
+}
+
+// Build downloads pip packages into the cache wheelhouse and system package artifacts
+// into the system directory, then records both in the cache manifest. Packages that
+// could not be downloaded are reported in the returned error.
+func Build(dir string, pm installer.PackageManager, pythonSetup *python.PythonSetup, pipPackages, systemPackages []string) (*Manifest, error) {
+	wheelDir := filepath.Join(dir, WheelDir)
+	systemDir := filepath.Join(dir, SystemDir)
+	for _, d := range []string{wheelDir, systemDir} {
+		if err := os.MkdirAll(d, 0755); err != nil {
+			return nil, fmt.Errorf("failed to create directory %s: %v", d, err)
+		}
+	}
+
+	manifest := &Manifest{
+		Created: time.Now().UTC(),
+		Manager: installer.ManagerName(pm),
+	}
+
+	fmt.Println("Downloading pip packages...")
+	failed := pythonSetup.DownloadPackages(wheelDir, pipPackages)
+	for _, pkg := range pipPackages {
+		if !contains(failed, pkg) {
+			manifest.Python = append(manifest.Python, pkg)
+		}
+	}
+
+	fmt.Println("Recording system packages...")
+	caching, canDownload := pm.(installer.CachingManager)
+	for _, pkg := range systemPackages {
+		entry := SystemArtifact{Name: pkg}
+		if canDownload {
+			artifact, err := caching.Download(pkg, systemDir)
+			if err != nil {
+				fmt.Printf("Warning: Failed to download %s: %v\n", pkg, err)
+				failed = append(failed, pkg)
+			} else if rel, err := filepath.Rel(dir, artifact); err == nil {
+				entry.Artifact = filepath.ToSlash(rel)
+			}
+		}
+		manifest.System = append(manifest.System, entry)
+	}
+	if !canDownload {
+		fmt.Printf("Note: %s cannot install from local files; system packages were recorded but not downloaded\n", manifest.Manager)
+	}
+
+	if err := manifest.Save(dir); err != nil {
+		return nil, fmt.Errorf("failed to write cache manifest: %v", err)
+	}
+
+	if len(failed) > 0 {
+		return manifest, fmt.Errorf("failed to cache %d package(s): %v", len(failed), failed)
+	}
+	return manifest, nil
+}
+
+// Missing lists the pip and system packages that neither are installed nor can be
+// installed from the cache
+func (m *Manifest) Missing(dir string, pm installer.PackageManager, pipPackages, systemPackages []string) []string {
+	var missing []string
+
+	available := wheelhouseContents(filepath.Join(dir, WheelDir))
+	for _, pkg := range pipPackages {
+		if !available[python.NormalizePackageName(RequirementName(pkg))] {
+			missing = append(missing, pkg+" (pip)")
+		}
+	}
+
+	for _, pkg := range systemPackages {
+		if pm.IsInstalled(pkg) {
+			continue
+		}
+		if _, ok := m.artifact(dir, pkg); !ok {
+			missing = append(missing, pkg+" (system)")
+		}
+	}
+
+	return missing
+}
+
+// artifact returns the path of a cached system package artifact, if it exists
+func (m *Manifest) artifact(dir, pkg string) (string, bool) {
+	for _, entry := range m.System {
+		if entry.Name != pkg || entry.Artifact == "" {
+			continue
+		}
+		path := filepath.Join(dir, filepath.FromSlash(entry.Artifact))
+		if _, err := os.Stat(path); err == nil {
+			return path, true
+		}
+	}
+	return "", false
+}
+
+// wheelhouseContents returns the normalized distribution names of the wheels and sdists in dir
+func wheelhouseContents(dir string) map[string]bool {
+	names := make(map[string]bool)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return names
+	}
+
+	for _, entry := range entries {
+		// Distribution files are named "<name>-<version>..." and names never contain "-<digit>"
+		name := entry.Name()
+		for i := 0; i+1 < len(name); i++ {
+			if name[i] == '-' && name[i+1] >= '0' && name[i+1] <= '9' {
+				names[python.NormalizePackageName(name[:i])] = true
+				break
+			}
+		}
+	}
+	return names
+}
+
+// RequirementName strips version specifiers, extras and markers from a requirement
+func RequirementName(requirement string) string {
+	end := strings.IndexAny(requirement, "<>=!~[;@ ")
+	if end < 0 {
+		return strings.TrimSpace(requirement)
+	}
+	return strings.TrimSpace(requirement[:end])
+}
+
+// ReadRequirements reads package requirements from a requirements-style manifest,
+// skipping comments, blank lines and pip options
+func ReadRequirements(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var requirements []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "-") {
+			continue
+		}
+		requirements = append(requirements, line)
+	}
+	return requirements, scanner.Err()
+}
+
+// OfflineManager wraps a package manager so system packages are installed from a local
+// cache instead of the network
+type OfflineManager struct {
+	installer.PackageManager
+	Dir      string
+	Manifest *Manifest
+}
+
+// Install skips packages that are already installed and installs others from the cache
+func (o *OfflineManager) Install(packageName string) error {
+	if o.PackageManager.IsInstalled(packageName) {
+		fmt.Printf("✓ %s is already installed\n", packageName)
+		return nil
+	}
+
+	artifact, ok := o.Manifest.artifact(o.Dir, packageName)
+	caching, canInstall := o.PackageManager.(installer.CachingManager)
+	if !ok || !canInstall {
+		return fmt.Errorf("%s is not available in the offline cache", packageName)
+	}
+	return caching.InstallArtifact(packageName, artifact)
+}
+
+// Update is unavailable offline
+func (o *OfflineManager) Update(packageName string) error {
+	return fmt.Errorf("cannot update %s in offline mode", packageName)
+}
+
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
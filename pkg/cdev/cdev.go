@@ -1,18 +1,41 @@
 package cdev
 
 import (
+	"embed"
 	"fmt"
-	"os"
 	"path/filepath"
+	"strings"
 
 	"devstation-cli/pkg/installer"
+	"devstation-cli/pkg/project"
 )
 
+// templates holds the C project scaffold; see project.Render
+//
+//go:embed all:templates
+var templates embed.FS
+
 // CDevSetup handles C development environment setup
 type CDevSetup struct {
 	PackageManager installer.PackageManager
 }
 
+// CompilerPackages lists the C compiler packages in order of preference
+var CompilerPackages = []string{
+	"mingw",                      // MinGW-w64
+	"visualstudio2022buildtools", // Visual Studio Build Tools
+}
+
+// DevelopmentTools lists the system packages installed alongside the compiler
+var DevelopmentTools = []string{
+	"cmake",        // Build system
+	"make",         // Make utility
+	"git",          // Version control
+	"vscode",       // IDE
+	"clang-format", // Code formatter
+	"gdb",          // Debugger
+}
+
 // NewCDevSetup creates a new C development setup instance
 func NewCDevSetup(pm installer.PackageManager) *CDevSetup {
 	return &CDevSetup{PackageManager: pm}
@@ -41,12 +64,12 @@ func (c *CDevSetup) installCompiler() error {
 	fmt.Println("Installing C compiler...")
 	
 	// Try to install MinGW-w64 first (more portable)
-	if err := c.PackageManager.Install("mingw"); err != nil {
+	if err := c.PackageManager.Install(CompilerPackages[0]); err != nil {
 		fmt.Printf("MinGW installation failed: %v\n", err)
 		
 		// Fallback to Visual Studio Build Tools
 		fmt.Println("Trying Visual Studio Build Tools...")
-		if err := c.PackageManager.Install("visualstudio2022buildtools"); err != nil {
+		if err := c.PackageManager.Install(CompilerPackages[1]); err != nil {
 			return fmt.Errorf("failed to install both MinGW and VS Build Tools: %v", err)
 		}
 	}
@@ -58,16 +81,7 @@ func (c *CDevSetup) installCompiler() error {
 func (c *CDevSetup) installDevelopmentTools() error {
 	fmt.Println("Installing C development tools...")
 	
-	tools := []string{
-		"cmake",           // Build system
-		"make",            // Make utility
-		"git",             // Version control
-		"vscode",          // IDE
-		"clang-format",    // Code formatter
-		"gdb",             // Debugger
-	}
-	
-	for _, tool := range tools {
+	for _, tool := range DevelopmentTools {
 		if err := c.PackageManager.Install(tool); err != nil {
 			fmt.Printf("Warning: Failed to install %s: %v\n", tool, err)
 		}
@@ -76,16 +90,78 @@ func (c *CDevSetup) installDevelopmentTools() error {
 	return nil
 }
 
-// CreateCProject creates a basic C project structure
-func (c *CDevSetup) CreateCProject(projectName string) error {
-	fmt.Printf("Creating C project structure for '%s'...\n", projectName)
+// reservedSources lists the C identifiers whose library sources and targets would collide
+// with the scaffold's src/main.c and test_main test executable
+var reservedSources = map[string]bool{"main": true, "test_main": true}
+
+// reservedTargets lists the lowercased project names that cannot name the scaffold's
+// executable target: names CMake reserves (policy CMP0037, with "test" reserved by
+// enable_testing), the utility targets of the Makefile and Visual Studio generators and
+// the scaffold's own test_main target
+var reservedTargets = map[string]bool{
+	"all": true, "clean": true, "help": true, "install": true, "package": true, "package_source": true, "test": true,
+	"depend": true, "edit_cache": true, "rebuild_cache": true, "list_install_components": true, "preinstall": true,
+	"all_build": true, "zero_check": true, "run_tests": true, "test_main": true,
+}
+
+// DefaultTemplate names the built-in C project scaffold
+const DefaultTemplate = "default"
+
+// DefaultTemplateDescription describes the built-in C project scaffold
+const DefaultTemplateDescription = "CMake and Make project with a library, an executable and tests"
+
+// RenderProject renders the files of a new C project from the embedded templates, then
+// opts.UserTemplate if set, without writing them; files are keyed by path relative to
+// the project directory
+func RenderProject(projectName string, opts project.Options) (map[string]string, error) {
+	name, err := project.ParseName(projectName)
+	if err != nil {
+		return nil, err
+	}
+	if reservedTargets[strings.ToLower(name.Name)] {
+		return nil, fmt.Errorf("invalid project name %q: the executable target %s is reserved by CMake or the scaffold, so the project would not configure", projectName, name.Name)
+	}
+	if reservedSources[name.CIdentifier] {
+		return nil, fmt.Errorf("invalid project name %q: the library %s would collide with the scaffold's main program and tests", projectName, name.CIdentifier)
+	}
+	if user := opts.UserTemplate; user != nil && user.Type != project.KindC {
+		return nil, fmt.Errorf("template %s is a %s template, not a C one", user.Name, user.Type)
+	}
 	
-	// Create project directory
-	if err := os.MkdirAll(projectName, 0755); err != nil {
-		return fmt.Errorf("failed to create project directory: %v", err)
+	data := project.NewData(name, opts, nil)
+	files, err := project.Render(templates, "templates", data, nil)
+	if err != nil {
+		return nil, err
+	}
+	if opts.UserTemplate != nil {
+		rendered, err := opts.UserTemplate.Render(data, nil)
+		if err != nil {
+			return nil, err
+		}
+		for path, content := range rendered {
+			files[path] = content
+		}
+	}
+	license, err := project.LicenseFile(data)
+	if err != nil {
+		return nil, err
 	}
+	if license != "" {
+		files["LICENSE"] = license
+	}
+	return files, nil
+}
+
+// CreateCProject creates a basic C project structure; opts.Mode decides how an existing,
+// non-empty project directory is treated
+func (c *CDevSetup) CreateCProject(projectName string, opts project.Options) error {
+	rendered, err := RenderProject(projectName, opts)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Creating C project structure for '%s'...\n", projectName)
 	
-	// Create subdirectories
+	// Subdirectories, including those that start out empty
 	dirs := []string{
 		filepath.Join(projectName, "src"),
 		filepath.Join(projectName, "include"),
@@ -93,29 +169,13 @@ func (c *CDevSetup) CreateCProject(projectName string) error {
 		filepath.Join(projectName, "build"),
 		filepath.Join(projectName, "docs"),
 	}
-	
-	for _, dir := range dirs {
-		if err := os.MkdirAll(dir, 0755); err != nil {
-			return fmt.Errorf("failed to create directory %s: %v", dir, err)
-		}
+	files := make(map[string]string, len(rendered))
+	for path, content := range rendered {
+		files[filepath.Join(projectName, path)] = content
 	}
 	
-	// Create essential files
-	files := map[string]string{
-		filepath.Join(projectName, "README.md"):              generateReadme(projectName),
-		filepath.Join(projectName, ".gitignore"):             cGitignore,
-		filepath.Join(projectName, "CMakeLists.txt"):         generateCMakeLists(projectName),
-		filepath.Join(projectName, "Makefile"):               generateMakefile(projectName),
-		filepath.Join(projectName, "src", "main.c"):          generateMainC(projectName),
-		filepath.Join(projectName, "src", projectName+".c"):  generateSourceC(projectName),
-		filepath.Join(projectName, "include", projectName+".h"): generateHeader(projectName),
-		filepath.Join(projectName, "tests", "test_main.c"):   generateTestC(projectName),
-	}
-	
-	for filename, content := range files {
-		if err := os.WriteFile(filename, []byte(content), 0644); err != nil {
-			return fmt.Errorf("failed to create file %s: %v", filename, err)
-		}
+	if err := project.Scaffold(projectName, dirs, files, opts.Mode); err != nil {
+		return err
 	}
 	
 	fmt.Printf("✓ C project '%s' created successfully!\n", projectName)
@@ -127,214 +187,3 @@ func (c *CDevSetup) CreateCProject(projectName string) error {
 	
 	return nil
 }
-
-// generateReadme generates a README.md file for the C project
-func generateReadme(projectName string) string {
-	return fmt.Sprintf("# %s\n\nA C project created with DevStation CLI.\n\n## Building\n\n### Using CMake\n```bash\nmkdir build\ncd build\ncmake ..\nmake\n```\n\n### Using Make directly\n```bash\nmake\n```\n\n## Running\n```bash\n./build/%s\n```\n\n## Testing\n```bash\nmake test\n```\n\n## Project Structure\n- `src/` - Source files\n- `include/` - Header files\n- `tests/` - Test files\n- `build/` - Build artifacts\n- `docs/` - Documentation\n", projectName, projectName)
-}
-
-// generateCMakeLists generates a CMakeLists.txt file
-func generateCMakeLists(projectName string) string {
-	return fmt.Sprintf(`cmake_minimum_required(VERSION 3.10)
-project(%s)
-
-# Set C standard
-set(CMAKE_C_STANDARD 11)
-set(CMAKE_C_STANDARD_REQUIRED ON)
-
-# Add include directory
-include_directories(include)
-
-# Add executable
-add_executable(%s src/main.c src/%s.c)
-
-# Add test executable
-add_executable(test_main tests/test_main.c src/%s.c)
-
-# Enable testing
-enable_testing()
-add_test(NAME test_main COMMAND test_main)
-`, projectName, projectName, projectName, projectName)
-}
-
-// generateMakefile generates a basic Makefile
-func generateMakefile(projectName string) string {
-	return fmt.Sprintf(`CC=gcc
-CFLAGS=-Wall -Wextra -std=c11 -Iinclude
-SRCDIR=src
-BUILDDIR=build
-TESTDIR=tests
-SOURCES=$(SRCDIR)/main.c $(SRCDIR)/%s.c
-
-# Default target
-all: $(BUILDDIR)/%s
-
-# Create build directory
-$(BUILDDIR):
-	mkdir -p $(BUILDDIR)
-
-# Build main executable
-$(BUILDDIR)/%s: $(SOURCES) | $(BUILDDIR)
-	$(CC) $(CFLAGS) -o $@ $(SOURCES)
-
-# Build and run tests
-test: $(BUILDDIR)/test_main
-	$(BUILDDIR)/test_main
-
-$(BUILDDIR)/test_main: $(TESTDIR)/test_main.c $(SRCDIR)/%s.c | $(BUILDDIR)
-	$(CC) $(CFLAGS) -o $@ $^
-
-# Clean build artifacts
-clean:
-	rm -rf $(BUILDDIR)
-
-# Run the program
-run: $(BUILDDIR)/%s
-	$(BUILDDIR)/%s
-
-.PHONY: all test clean run
-`, projectName, projectName, projectName, projectName, projectName, projectName)
-}
-
-// generateMainC generates a main.c file
-func generateMainC(projectName string) string {
-	return fmt.Sprintf(`#include <stdio.h>
-#include "%s.h"
-
-int main(void) {
-    printf("Hello from %s!\\n");
-    
-    // Call a function from your header
-    greet();
-    
-    return 0;
-}
-`, projectName, projectName)
-}
-
-// generateSourceC generates a source file with function implementations
-func generateSourceC(projectName string) string {
-	return fmt.Sprintf(`#include <stdio.h>
-#include "%s.h"
-
-void greet(void) {
-    printf("Hello from %s library!\\n");
-}
-`, projectName, projectName)
-}
-
-// generateHeader generates a header file
-func generateHeader(projectName string) string {
-	return fmt.Sprintf(`#ifndef %s_H
-#define %s_H
-
-// Function prototypes
-void greet(void);
-
-#endif // %s_H
-`, projectName, projectName, projectName)
-}
-
-// generateTestC generates a basic test file
-func generateTestC(projectName string) string {
-	return fmt.Sprintf(`#include <stdio.h>
-#include <assert.h>
-#include "%s.h"
-
-void test_basic_functionality(void) {
-    // Add your tests here
-    printf("Running basic functionality test...\\n");
-    // Example: assert(some_function() == expected_value);
-    printf("✓ Basic functionality test passed\\n");
-}
-
-int main(void) {
-    printf("Running tests for %s...\\n");
-    
-    test_basic_functionality();
-    
-    printf("All tests passed!\\n");
-    return 0;
-}
-`, projectName, projectName)
-}
-
-// cGitignore contains a comprehensive .gitignore for C projects
-const cGitignore = `# Object files
-*.o
-*.ko
-*.obj
-*.elf
-
-# Linker output
-*.ilk
-*.map
-*.exp
-
-# Precompiled Headers
-*.gch
-*.pch
-
-# Libraries
-*.lib
-*.a
-*.la
-*.lo
-
-# Shared objects (inc. Windows DLLs)
-*.dll
-*.so
-*.so.*
-*.dylib
-
-# Executables
-*.exe
-*.out
-*.app
-*.i*86
-*.x86_64
-*.hex
-
-# Debug files
-*.dSYM/
-*.su
-*.idb
-*.pdb
-
-# Build directories
-build/
-Build/
-BUILD/
-out/
-bin/
-obj/
-
-# CMake
-CMakeCache.txt
-CMakeFiles/
-cmake_install.cmake
-Makefile
-*.cmake
-!CMakeLists.txt
-
-# IDE files
-.vscode/
-.idea/
-*.swp
-*.swo
-*~
-
-# OS files
-.DS_Store
-Thumbs.db
-
-# Core dumps
-core
-*.core
-
-# Temporary files
-*.tmp
-*.temp
-*.bak
-*.backup
-`
@@ -0,0 +1,180 @@
+package cdev
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"devstation-cli/pkg/project"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata/golden")
+
+func TestRenderProjectGolden(t *testing.T) {
+	tests := []struct {
+		name    string
+		project string
+		opts    project.Options
+	}{
+		{"my-lib", "my-lib", project.Options{Author: "Jane Doe"}},
+		{"my-lib-mit", "my-lib", project.Options{Author: "Jane Doe", License: "MIT"}},
+		{"digits-bsd", "2d.engine", project.Options{Author: "Jane Doe", License: "BSD-3-Clause"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files, err := RenderProject(tt.project, tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkGolden(t, filepath.Join("testdata", "golden", tt.name), files)
+		})
+	}
+}
+
+func TestRenderProjectErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		project string
+		opts    project.Options
+		want    string
+	}{
+		{"path", "../lib", project.Options{}, "not a path"},
+		{"main", "main", project.Options{}, "collide"},
+		{"cmake test target", "test", project.Options{}, "reserved by CMake"},
+		{"cmake all target", "ALL", project.Options{}, "reserved by CMake"},
+		{"cmake install target", "install", project.Options{}, "reserved by CMake"},
+		{"cmake package target", "package", project.Options{}, "reserved by CMake"},
+		{"cmake help target", "help", project.Options{}, "reserved by CMake"},
+		{"cmake clean target", "clean", project.Options{}, "reserved by CMake"},
+		{"visual studio target", "ZERO_CHECK", project.Options{}, "reserved by CMake"},
+		{"scaffold test target", "test_main", project.Options{}, "reserved by CMake or the scaffold"},
+		{"test main", "Test-Main", project.Options{}, "collide"},
+		{"license", "lib", project.Options{License: "nope"}, "no license text"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := RenderProject(tt.project, tt.opts)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("RenderProject(%q) error = %v, want it to contain %q", tt.project, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderProjectUserTemplate(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(project.ManifestFile, `{"name": "firmware", "type": "c", "variables": [{"name": "board"}]}`)
+	write("README.md.tmpl", "# {{.Name}} for {{.Vars.board}}\n")
+	user, err := project.LoadTemplate(dir, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	files, err := RenderProject("fw", project.Options{UserTemplate: &user, Vars: map[string]string{"board": "stm32"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := files["README.md"]; got != "# fw for stm32\n" {
+		t.Errorf("README.md = %q, want the user template's version", got)
+	}
+	if _, ok := files["Makefile"]; !ok {
+		t.Error("built-in Makefile missing from a project rendered with a user template")
+	}
+
+	if _, err := RenderProject("fw", project.Options{UserTemplate: &user}); err == nil {
+		t.Error("RenderProject() without the template's variables succeeded")
+	}
+	user.Type = project.KindPython
+	if _, err := RenderProject("fw", project.Options{UserTemplate: &user}); err == nil {
+		t.Error("RenderProject() with a Python template succeeded")
+	}
+}
+
+// goldenYear replaces the current year in rendered files so the golden files stay stable
+const goldenYear = "YYYY"
+
+// checkGolden compares rendered files with the golden tree in dir, or rewrites the tree with
+// -update. .gitignore files are stored as _gitignore so they do not apply to the golden tree.
+func checkGolden(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	year := strconv.Itoa(time.Now().Year())
+	want := make(map[string]string)
+	for path, content := range files {
+		want[goldenPath(path)] = strings.ReplaceAll(content, year, goldenYear)
+	}
+
+	if *update {
+		if err := os.RemoveAll(dir); err != nil {
+			t.Fatal(err)
+		}
+		for path, content := range want {
+			target := filepath.Join(dir, filepath.FromSlash(path))
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(target, []byte(content), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return
+	}
+
+	got := make(map[string]string)
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(dir, path)
+		got[filepath.ToSlash(rel)] = string(content)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("reading golden files (run go test -update to create them): %v", err)
+	}
+
+	var paths []string
+	for path := range want {
+		paths = append(paths, path)
+	}
+	for path := range got {
+		if _, ok := want[path]; !ok {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		rendered, isRendered := want[path]
+		golden, isGolden := got[path]
+		switch {
+		case !isGolden:
+			t.Errorf("%s: rendered but not in the golden tree", path)
+		case !isRendered:
+			t.Errorf("%s: in the golden tree but not rendered", path)
+		case rendered != golden:
+			t.Errorf("%s differs from the golden file:\n%s", path, project.Diff(golden, rendered))
+		}
+	}
+}
+
+// goldenPath returns the slash-separated golden tree path of a rendered file
+func goldenPath(path string) string {
+	path = filepath.ToSlash(path)
+	if dir, base := filepath.Split(path); base == ".gitignore" {
+		return dir + "_gitignore"
+	}
+	return path
+}
@@ -0,0 +1,7 @@
+#ifndef _2D_ENGINE_H
+#define _2D_ENGINE_H
+
+// Function prototypes
+void greet(void);
+
+#endif // _2D_ENGINE_H
@@ -0,0 +1,6 @@
+#include <stdio.h>
+#include "_2d_engine.h"
+
+void greet(void) {
+    printf("Hello from 2d.engine library!\n");
+}
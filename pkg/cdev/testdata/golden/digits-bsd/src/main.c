@@ -0,0 +1,11 @@
+#include <stdio.h>
+#include "_2d_engine.h"
+
+int main(void) {
+    printf("Hello from 2d.engine!\n");
+    
+    // Call a function from your header
+    greet();
+    
+    return 0;
+}
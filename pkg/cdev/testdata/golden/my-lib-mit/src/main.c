@@ -0,0 +1,11 @@
+#include <stdio.h>
+#include "my_lib.h"
+
+int main(void) {
+    printf("Hello from my-lib!\n");
+    
+    // Call a function from your header
+    greet();
+    
+    return 0;
+}
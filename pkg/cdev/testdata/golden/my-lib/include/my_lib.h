@@ -0,0 +1,7 @@
+#ifndef MY_LIB_H
+#define MY_LIB_H
+
+// Function prototypes
+void greet(void);
+
+#endif // MY_LIB_H
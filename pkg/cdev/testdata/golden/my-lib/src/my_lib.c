@@ -0,0 +1,6 @@
+#include <stdio.h>
+#include "my_lib.h"
+
+void greet(void) {
+    printf("Hello from my-lib library!\n");
+}
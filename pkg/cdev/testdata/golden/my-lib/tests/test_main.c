@@ -0,0 +1,19 @@
+#include <stdio.h>
+#include <assert.h>
+#include "my_lib.h"
+
+void test_basic_functionality(void) {
+    // Add your tests here
+    printf("Running basic functionality test...\n");
+    // Example: assert(some_function() == expected_value);
+    printf("✓ Basic functionality test passed\n");
+}
+
+int main(void) {
+    printf("Running tests for my-lib...\n");
+    
+    test_basic_functionality();
+    
+    printf("All tests passed!\n");
+    return 0;
+}
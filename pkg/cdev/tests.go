@@ -0,0 +1,160 @@
+package cdev
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"devstation-cli/pkg/testrun"
+)
+
+// BuildDir is the CMake build directory used for tests, matching the generated README
+const BuildDir = "build"
+
+// BuildType is the configuration tests are built and run in. Multi-config generators such as
+// Visual Studio need it passed to both the build and CTest, or CTest finds no executables.
+const BuildType = "Debug"
+
+// FindProject walks up from start to the nearest directory containing a CMakeLists.txt or Makefile
+func FindProject(start string) (string, error) {
+	dir, err := filepath.Abs(start)
+	if err != nil {
+		return "", err
+	}
+	for {
+		for _, marker := range []string{"CMakeLists.txt", "Makefile"} {
+			if info, err := os.Stat(filepath.Join(dir, marker)); err == nil && !info.IsDir() {
+				return dir, nil
+			}
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", fmt.Errorf("no C project found (looked for CMakeLists.txt or Makefile in %s and its parents)", start)
+		}
+		dir = parent
+	}
+}
+
+// RunTests builds the project and runs its tests: with CMake and CTest when the project has a
+// CMakeLists.txt and cmake is installed, otherwise with the Makefile's test target. The returned
+// error is only set when no build tool is available; build failures are reported in Suite.Err.
+func RunTests(projectDir string) (testrun.Suite, error) {
+	if _, err := os.Stat(filepath.Join(projectDir, "CMakeLists.txt")); err == nil {
+		if _, err := exec.LookPath("cmake"); err == nil {
+			return runCTest(projectDir), nil
+		}
+	}
+	if _, err := os.Stat(filepath.Join(projectDir, "Makefile")); err == nil {
+		if makeTool, err := makeCommand(); err == nil {
+			return runMakeTest(projectDir, makeTool), nil
+		}
+	}
+	return testrun.Suite{}, fmt.Errorf("neither cmake (for CMakeLists.txt) nor make (for Makefile) is installed; run 'devstation setup c'")
+}
+
+// makeCommand returns the make executable, which MinGW installs as mingw32-make
+func makeCommand() (string, error) {
+	for _, name := range []string{"make", "mingw32-make"} {
+		if path, err := exec.LookPath(name); err == nil {
+			return path, nil
+		}
+	}
+	return "", fmt.Errorf("make not found")
+}
+
+// runCTest configures and builds the project in BuildDir and runs CTest
+func runCTest(projectDir string) testrun.Suite {
+	suite := testrun.Suite{Name: "ctest"}
+	start := time.Now()
+
+	steps := [][]string{
+		{"cmake", "-S", ".", "-B", BuildDir, "-DCMAKE_BUILD_TYPE=" + BuildType},
+		{"cmake", "--build", BuildDir, "--config", BuildType},
+	}
+	for _, step := range steps {
+		fmt.Printf("Running %s...\n", strings.Join(step, " "))
+		cmd := exec.Command(step[0], step[1:]...)
+		cmd.Dir = projectDir
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		if err := cmd.Run(); err != nil {
+			suite.Err = fmt.Errorf("%s failed: %v", strings.Join(step, " "), err)
+			suite.Duration = time.Since(start)
+			return suite
+		}
+	}
+
+	var output bytes.Buffer
+	cmd := exec.Command("ctest", "--output-on-failure", "-C", BuildType)
+	cmd.Dir = filepath.Join(projectDir, BuildDir)
+	cmd.Stdout = io.MultiWriter(os.Stdout, &output)
+	cmd.Stderr = os.Stderr
+	runErr := cmd.Run()
+
+	suite.Cases = parseCTestOutput(output.String())
+	// A failing exit without failed tests means CTest itself could not run them
+	if _, failed, _ := suite.Counts(); runErr != nil && failed == 0 {
+		suite.Err = fmt.Errorf("ctest failed: %v", runErr)
+	}
+	suite.Duration = time.Since(start)
+	return suite
+}
+
+// ctestResult matches CTest's per-test lines, e.g.
+// "1/2 Test #1: test_main ........................   Passed    0.01 sec"
+// "2/2 Test #2: test_io ..........................***Failed    0.02 sec"
+// "3/3 Test #3: test_gpu .........................***Not Run (Disabled)   0.00 sec"
+var ctestResult = regexp.MustCompile(`(?m)^\s*\d+/\d+ Test\s+#\d+: (\S+) \.*\s*(.*?)\s+([\d.]+) sec\s*$`)
+
+// parseCTestOutput extracts the test cases from CTest's console output. Disabled and skipped
+// tests are skipped; any other "Not Run", e.g. a missing test executable, is a failure as
+// CTest counts it.
+func parseCTestOutput(output string) []testrun.Case {
+	var cases []testrun.Case
+	for _, match := range ctestResult.FindAllStringSubmatch(output, -1) {
+		seconds, _ := strconv.ParseFloat(match[3], 64)
+		c := testrun.Case{
+			Name:      match[1],
+			Classname: "ctest",
+			Duration:  time.Duration(seconds * float64(time.Second)),
+		}
+		status := strings.TrimLeft(match[2], "*")
+		switch {
+		case status == "Passed":
+		case status == "Not Run (Disabled)", strings.HasPrefix(status, "Disabled"), strings.HasPrefix(status, "Skipped"):
+			c.Skipped = true
+		default:
+			c.Failure = status
+		}
+		cases = append(cases, c)
+	}
+	return cases
+}
+
+// runMakeTest runs the Makefile's test target, which builds and runs the test executable.
+// The target reports no individual tests, so it is recorded as a single case.
+func runMakeTest(projectDir, makeTool string) testrun.Suite {
+	suite := testrun.Suite{Name: "make"}
+	fmt.Println("Running make test...")
+	cmd := exec.Command(makeTool, "test")
+	cmd.Dir = projectDir
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	start := time.Now()
+	err := cmd.Run()
+	suite.Duration = time.Since(start)
+
+	c := testrun.Case{Name: "test", Classname: "make", Duration: suite.Duration}
+	if err != nil {
+		c.Failure = fmt.Sprintf("make test failed: %v", err)
+	}
+	suite.Cases = []testrun.Case{c}
+	return suite
+}
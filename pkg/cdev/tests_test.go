@@ -0,0 +1,72 @@
+package cdev
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"devstation-cli/pkg/testrun"
+)
+
+func TestParseCTestOutput(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   []testrun.Case
+	}{
+		{
+			name:   "no tests",
+			output: "Test project /src/build\nNo tests were found!!!\n",
+			want:   nil,
+		},
+		{
+			name: "passed and failed",
+			output: `Test project /src/build
+    Start 1: test_main
+1/2 Test #1: test_main ........................   Passed    0.01 sec
+    Start 2: test_io
+2/2 Test #2: test_io ..........................***Failed    0.25 sec
+assertion failed
+
+50% tests passed, 1 tests failed out of 2
+`,
+			want: []testrun.Case{
+				{Name: "test_main", Classname: "ctest", Duration: 10 * time.Millisecond},
+				{Name: "test_io", Classname: "ctest", Duration: 250 * time.Millisecond, Failure: "Failed"},
+			},
+		},
+		{
+			name: "not run is a failure",
+			output: `1/1 Test #1: test_main ........................***Not Run   0.00 sec
+`,
+			want: []testrun.Case{{Name: "test_main", Classname: "ctest", Failure: "Not Run"}},
+		},
+		{
+			name: "disabled and skipped",
+			output: `1/2 Test #1: test_gpu .........................***Not Run (Disabled)   0.00 sec
+2/2 Test #2: test_net .........................***Skipped   0.00 sec
+`,
+			want: []testrun.Case{
+				{Name: "test_gpu", Classname: "ctest", Skipped: true},
+				{Name: "test_net", Classname: "ctest", Skipped: true},
+			},
+		},
+		{
+			name: "exception and timeout",
+			output: ` 1/2 Test  #1: test_crash .......................***Exception: SegFault  0.02 sec
+ 2/2 Test  #2: test_slow ........................***Timeout  10.00 sec
+`,
+			want: []testrun.Case{
+				{Name: "test_crash", Classname: "ctest", Duration: 20 * time.Millisecond, Failure: "Exception: SegFault"},
+				{Name: "test_slow", Classname: "ctest", Duration: 10 * time.Second, Failure: "Timeout"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseCTestOutput(tt.output); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseCTestOutput() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,384 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Config holds the user's devstation settings
+type Config struct {
+	Pip      PipConfig    `json:"pip"`
+	Proxy    string       `json:"proxy,omitempty"`
+	CABundle string       `json:"ca_bundle,omitempty"`
+	Python   PythonConfig `json:"python"`
+	Venv     VenvConfig   `json:"venv"`
+	Tools    ToolsConfig  `json:"tools"`
+	// Templates lists where user-defined project templates are found
+	Templates TemplatesConfig `json:"templates"`
+	// Repositories are the package indexes 'devstation publish' uploads to, by name
+	Repositories map[string]Repository `json:"repositories,omitempty"`
+}
+
+// PipConfig holds the package index settings applied to every pip invocation
+type PipConfig struct {
+	IndexURL       string   `json:"index_url,omitempty"`
+	ExtraIndexURLs []string `json:"extra_index_urls,omitempty"`
+	TrustedHosts   []string `json:"trusted_hosts,omitempty"`
+}
+
+// PythonConfig records the Python interpreters devstation manages
+type PythonConfig struct {
+	Default      string        `json:"default,omitempty"` // Path of the default interpreter
+	Interpreters []Interpreter `json:"interpreters,omitempty"`
+}
+
+// Interpreter is a Python interpreter registered with devstation
+type Interpreter struct {
+	Version string `json:"version"` // Full version, e.g. 3.11.7
+	Path    string `json:"path"`
+}
+
+// AddInterpreter registers an interpreter, replacing any entry with the same path
+func (c *PythonConfig) AddInterpreter(interpreter Interpreter) {
+	for i, existing := range c.Interpreters {
+		if existing.Path == interpreter.Path {
+			c.Interpreters[i] = interpreter
+			return
+		}
+	}
+	c.Interpreters = append(c.Interpreters, interpreter)
+}
+
+// VenvConfig controls where project virtual environments are created
+type VenvConfig struct {
+	Location   string `json:"location,omitempty"`    // "project" (default) or "central"
+	Name       string `json:"name,omitempty"`        // In-project directory name
+	CentralDir string `json:"central_dir,omitempty"` // Root directory for central venvs
+}
+
+// ToolsConfig controls where isolated Python tool environments are kept
+type ToolsConfig struct {
+	Dir string `json:"dir,omitempty"` // Root directory for tool environments and shims
+}
+
+// TemplatesConfig holds the sources of user-defined project templates
+type TemplatesConfig struct {
+	Sources []string `json:"sources,omitempty"` // Template directories, or git+PATH repositories to clone
+}
+
+// Repository is a package index distributions can be published to
+type Repository struct {
+	URL      string `json:"url,omitempty"`      // Upload URL, or a local directory served as a simple index
+	Username string `json:"username,omitempty"` // Upload user; the password comes from TWINE_PASSWORD or a prompt
+}
+
+// Key describes a configuration key that can be read and written by name
+type Key struct {
+	Name        string
+	Description string
+	List        bool     // Comma-separated list value
+	Choices     []string // Allowed values, if restricted
+	get         func(c *Config) []string
+	set         func(c *Config, values []string)
+}
+
+// Keys lists every configuration key, in display order
+var Keys = []Key{
+	{
+		Name:        "pip.index-url",
+		Description: "Base URL of the Python package index",
+		get:         func(c *Config) []string { return single(c.Pip.IndexURL) },
+		set:         func(c *Config, v []string) { c.Pip.IndexURL = first(v) },
+	},
+	{
+		Name:        "pip.extra-index-urls",
+		Description: "Additional package index URLs",
+		List:        true,
+		get:         func(c *Config) []string { return c.Pip.ExtraIndexURLs },
+		set:         func(c *Config, v []string) { c.Pip.ExtraIndexURLs = v },
+	},
+	{
+		Name:        "pip.trusted-hosts",
+		Description: "Hosts pip trusts without valid HTTPS",
+		List:        true,
+		get:         func(c *Config) []string { return c.Pip.TrustedHosts },
+		set:         func(c *Config, v []string) { c.Pip.TrustedHosts = v },
+	},
+	{
+		Name:        "python.default",
+		Description: "Path of the default Python interpreter",
+		get:         func(c *Config) []string { return single(c.Python.Default) },
+		set:         func(c *Config, v []string) { c.Python.Default = first(v) },
+	},
+	{
+		Name:        "venv.location",
+		Description: "Where project venvs live: project or central",
+		Choices:     []string{"project", "central"},
+		get:         func(c *Config) []string { return single(c.Venv.Location) },
+		set:         func(c *Config, v []string) { c.Venv.Location = first(v) },
+	},
+	{
+		Name:        "venv.name",
+		Description: "Directory name of in-project venvs (default .venv)",
+		get:         func(c *Config) []string { return single(c.Venv.Name) },
+		set:         func(c *Config, v []string) { c.Venv.Name = first(v) },
+	},
+	{
+		Name:        "venv.central-dir",
+		Description: "Root directory of central venvs (default user cache dir)",
+		get:         func(c *Config) []string { return single(c.Venv.CentralDir) },
+		set:         func(c *Config, v []string) { c.Venv.CentralDir = first(v) },
+	},
+	{
+		Name:        "tools.dir",
+		Description: "Root directory of Python tool environments and their shims",
+		get:         func(c *Config) []string { return single(c.Tools.Dir) },
+		set:         func(c *Config, v []string) { c.Tools.Dir = first(v) },
+	},
+	{
+		Name:        "templates.sources",
+		Description: "Directories or git+PATH repositories with project templates",
+		List:        true,
+		get:         func(c *Config) []string { return c.Templates.Sources },
+		set:         func(c *Config, v []string) { c.Templates.Sources = v },
+	},
+	{
+		Name:        "proxy",
+		Description: "HTTP(S) proxy URL for pip and system package managers",
+		get:         func(c *Config) []string { return single(c.Proxy) },
+		set:         func(c *Config, v []string) { c.Proxy = first(v) },
+	},
+	{
+		Name:        "ca-bundle",
+		Description: "PEM file of CA certificates to trust for HTTPS",
+		get:         func(c *Config) []string { return single(c.CABundle) },
+		set:         func(c *Config, v []string) { c.CABundle = first(v) },
+	},
+}
+
+var (
+	current     *Config
+	currentOnce sync.Once
+)
+
+// Path returns the location of the configuration file, which can be overridden with
+// the DEVSTATION_CONFIG environment variable
+func Path() (string, error) {
+	if path := os.Getenv("DEVSTATION_CONFIG"); path != "" {
+		return path, nil
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate user config directory: %v", err)
+	}
+	return filepath.Join(dir, "devstation", "config.json"), nil
+}
+
+// Load reads the configuration file; a missing file yields an empty configuration
+func Load() (*Config, error) {
+	path, err := Path()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return &Config{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config: %v", err)
+	}
+
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse config %s: %v", path, err)
+	}
+	return &cfg, nil
+}
+
+// Current returns the configuration loaded once per run. If the file cannot be read a
+// warning is printed and an empty configuration is used.
+func Current() *Config {
+	currentOnce.Do(func() {
+		cfg, err := Load()
+		if err != nil {
+			fmt.Printf("Warning: %v\n", err)
+			cfg = &Config{}
+		}
+		current = cfg
+	})
+	return current
+}
+
+// Save writes the configuration file
+func (c *Config) Save() error {
+	path, err := Path()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %v", err)
+	}
+
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// Get returns the value of a configuration key, with list values joined by commas
+func (c *Config) Get(name string) (string, error) {
+	key, err := lookup(name)
+	if err != nil {
+		return "", err
+	}
+	return strings.Join(key.get(c), ","), nil
+}
+
+// Set assigns a configuration key; list keys take a comma-separated value
+func (c *Config) Set(name, value string) error {
+	key, err := lookup(name)
+	if err != nil {
+		return err
+	}
+
+	var values []string
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	if !key.List && len(values) > 1 {
+		return fmt.Errorf("%s takes a single value", name)
+	}
+	for _, v := range values {
+		if len(key.Choices) > 0 && !contains(key.Choices, v) {
+			return fmt.Errorf("invalid value %q for %s; expected one of %s", v, name, strings.Join(key.Choices, ", "))
+		}
+	}
+
+	key.set(c, values)
+	return nil
+}
+
+// Unset clears a configuration key
+func (c *Config) Unset(name string) error {
+	key, err := lookup(name)
+	if err != nil {
+		return err
+	}
+	key.set(c, nil)
+	return nil
+}
+
+// AllKeys returns the fixed keys followed by the keys of every configured repository
+func (c *Config) AllKeys() []Key {
+	keys := append([]Key{}, Keys...)
+	var names []string
+	for name := range c.Repositories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		for _, field := range repositoryFields {
+			key, _ := repositoryKey("repository." + name + "." + field)
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// repositoryFields are the settings of each repository, keyed as repository.<name>.<field>
+var repositoryFields = []string{"url", "username"}
+
+// repositoryKey builds the key for a repository setting such as "repository.local.url"
+func repositoryKey(name string) (Key, bool) {
+	parts := strings.Split(name, ".")
+	if len(parts) != 3 || parts[0] != "repository" || parts[1] == "" {
+		return Key{}, false
+	}
+	repo, field := parts[1], parts[2]
+
+	var description string
+	var get func(r Repository) string
+	var update func(r *Repository, value string)
+	switch field {
+	case "url":
+		description = "upload URL or local directory"
+		get = func(r Repository) string { return r.URL }
+		update = func(r *Repository, value string) { r.URL = value }
+	case "username":
+		description = "upload user"
+		get = func(r Repository) string { return r.Username }
+		update = func(r *Repository, value string) { r.Username = value }
+	default:
+		return Key{}, false
+	}
+
+	return Key{
+		Name:        name,
+		Description: fmt.Sprintf("Repository %s: %s", repo, description),
+		get:         func(c *Config) []string { return single(get(c.Repositories[repo])) },
+		set: func(c *Config, v []string) {
+			r := c.Repositories[repo]
+			update(&r, first(v))
+			if r == (Repository{}) {
+				delete(c.Repositories, repo)
+				return
+			}
+			if c.Repositories == nil {
+				c.Repositories = make(map[string]Repository)
+			}
+			c.Repositories[repo] = r
+		},
+	}, true
+}
+
+// lookup finds a configuration key by name
+func lookup(name string) (Key, error) {
+	for _, key := range Keys {
+		if key.Name == name {
+			return key, nil
+		}
+	}
+	if key, ok := repositoryKey(name); ok {
+		return key, nil
+	}
+
+	var names []string
+	for _, key := range Keys {
+		names = append(names, key.Name)
+	}
+	sort.Strings(names)
+	names = append(names, "repository.<name>.url", "repository.<name>.username")
+	return Key{}, fmt.Errorf("unknown config key %q; valid keys: %s", name, strings.Join(names, ", "))
+}
+
+func single(value string) []string {
+	if value == "" {
+		return nil
+	}
+	return []string{value}
+}
+
+func first(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,199 @@
+package installer
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// BootstrapScript describes the installer script used to bootstrap a package manager
+type BootstrapScript struct {
+	Manager  string   // Name shown to the user
+	Command  string   // Command available once the manager is installed
+	Source   string   // Path or URL of the installer script
+	SHA256   string   // Checksum the script must match, from --bootstrap-sha256
+	Elevated bool     // Whether the installer must run elevated
+	Run      []string // Interpreter invocation; the script path is appended
+}
+
+// BootstrapScripts lists the package managers devstation can bootstrap. The default sources
+// are the upstream installer URLs, which change without notice, so devstation ships no
+// checksum for them: teams pin the revision they reviewed with --bootstrap-sha256, and point
+// --bootstrap-script at their own copy when they want a fixed source (or set
+// DEVSTATION_BOOTSTRAP_SCRIPT/DEVSTATION_BOOTSTRAP_SHA256). A script that does not match
+// the checksum is never run.
+var BootstrapScripts = map[string]BootstrapScript{
+	"choco": {
+		Manager:  "Chocolatey",
+		Command:  "choco",
+		Source:   "https://community.chocolatey.org/install.ps1",
+		Elevated: true,
+		Run:      []string{"powershell", "-NoProfile", "-ExecutionPolicy", "Bypass", "-File"},
+	},
+	"scoop": {
+		Manager: "Scoop",
+		Command: "scoop",
+		Source:  "https://raw.githubusercontent.com/ScoopInstaller/Install/master/install.ps1",
+		Run:     []string{"powershell", "-NoProfile", "-ExecutionPolicy", "Bypass", "-File"},
+	},
+	"brew": {
+		Manager: "Homebrew",
+		Command: "brew",
+		Source:  "https://raw.githubusercontent.com/Homebrew/install/HEAD/install.sh",
+		Run:     []string{"/bin/bash"},
+	},
+}
+
+// BootstrapOptions controls how a missing package manager is bootstrapped
+type BootstrapOptions struct {
+	Allow   bool   // Explicit confirmation that a package manager may be installed
+	Manager string // Manager to bootstrap; defaults to the preferred one for the OS
+	Source  string // Path or URL overriding the default installer script location
+	SHA256  string // Pinned checksum of the installer script
+}
+
+// DefaultBootstrapManager returns the package manager bootstrapped by default on this OS
+func DefaultBootstrapManager() string {
+	switch runtime.GOOS {
+	case "windows":
+		if IsElevated() {
+			return "choco"
+		}
+		return "scoop"
+	case "darwin":
+		return "brew"
+	}
+	return ""
+}
+
+// InstallPackageManager bootstraps a package manager if none is available. The installer
+// script is fetched, verified against the SHA-256 the user pinned and only executed when
+// bootstrapping has been explicitly allowed.
+func InstallPackageManager(opts BootstrapOptions) error {
+	if GetAvailablePackageManager() != nil {
+		return nil
+	}
+
+	if opts.Manager == "" {
+		opts.Manager = DefaultBootstrapManager()
+	}
+	if opts.Manager == "" {
+		return fmt.Errorf("no package manager found and none can be bootstrapped on %s; install one with your system tools", runtime.GOOS)
+	}
+
+	script, err := resolveBootstrapScript(opts)
+	if err != nil {
+		return err
+	}
+	if script.Elevated && !IsElevated() {
+		return elevationRequiredError(script.Manager)
+	}
+
+	return runBootstrapScript(script)
+}
+
+// resolveBootstrapScript returns the installer script of opts.Manager with the script and
+// checksum of opts or the environment applied. The checksum is always required, since no
+// default script comes with one.
+func resolveBootstrapScript(opts BootstrapOptions) (BootstrapScript, error) {
+	script, ok := BootstrapScripts[opts.Manager]
+	if !ok {
+		return BootstrapScript{}, fmt.Errorf("unknown package manager %q; expected one of choco, scoop, brew", opts.Manager)
+	}
+	if opts.Source == "" {
+		opts.Source = os.Getenv("DEVSTATION_BOOTSTRAP_SCRIPT")
+	}
+	if opts.Source != "" {
+		script.Source = opts.Source
+	}
+	if opts.SHA256 == "" {
+		opts.SHA256 = os.Getenv("DEVSTATION_BOOTSTRAP_SHA256")
+	}
+	script.SHA256 = strings.ToLower(strings.TrimSpace(opts.SHA256))
+
+	if !opts.Allow {
+		return script, fmt.Errorf("no package manager found; re-run with --allow-bootstrap to install %s from %s", script.Manager, script.Source)
+	}
+	if script.SHA256 == "" {
+		return script, fmt.Errorf("no pinned SHA-256 for %s; pass --bootstrap-sha256 or set DEVSTATION_BOOTSTRAP_SHA256", script.Source)
+	}
+	return script, nil
+}
+
+// runBootstrapScript fetches, verifies and executes a package manager installer script
+func runBootstrapScript(script BootstrapScript) error {
+	fmt.Printf("Bootstrapping %s from %s...\n", script.Manager, script.Source)
+
+	content, err := fetchBootstrapScript(script.Source)
+	if err != nil {
+		return fmt.Errorf("failed to fetch installer script: %v", err)
+	}
+
+	sum := sha256.Sum256(content)
+	if actual := hex.EncodeToString(sum[:]); actual != script.SHA256 {
+		return fmt.Errorf("installer script checksum mismatch: expected %s, got %s", script.SHA256, actual)
+	}
+	fmt.Println("✓ Installer script checksum verified")
+
+	dir, err := os.MkdirTemp("", "devstation-bootstrap")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "install"+scriptExtension(script))
+	if err := os.WriteFile(path, content, 0700); err != nil {
+		return fmt.Errorf("failed to write installer script: %v", err)
+	}
+
+	cmd := exec.Command(script.Run[0], append(script.Run[1:], path)...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Stdin = os.Stdin
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%s installer failed: %v", script.Manager, err)
+	}
+
+	if !isCommandAvailable(script.Command) {
+		fmt.Printf("Warning: %s was installed but is not on PATH yet; open a new terminal\n", script.Manager)
+	}
+	return nil
+}
+
+// fetchBootstrapScript reads an installer script from a local path or an http(s) URL
+func fetchBootstrapScript(source string) ([]byte, error) {
+	if !strings.HasPrefix(source, "https://") && !strings.HasPrefix(source, "http://") {
+		return os.ReadFile(source)
+	}
+
+	client, err := httpClient(60 * time.Second)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Get(source)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected HTTP status %s", resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// scriptExtension returns the file extension expected by the installer's interpreter
+func scriptExtension(script BootstrapScript) string {
+	if script.Run[0] == "powershell" {
+		return ".ps1"
+	}
+	return ".sh"
+}
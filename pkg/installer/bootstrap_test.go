@@ -0,0 +1,155 @@
+package installer
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestBootstrapScriptDefaults(t *testing.T) {
+	for _, name := range []string{"choco", "scoop", "brew"} {
+		script, ok := BootstrapScripts[name]
+		if !ok {
+			t.Errorf("%s: no bootstrap script", name)
+			continue
+		}
+		if !strings.HasPrefix(script.Source, "https://") {
+			t.Errorf("%s: default source %q is not an https URL", name, script.Source)
+		}
+		// Upstream installer URLs are unversioned, so a shipped checksum would go stale
+		if script.SHA256 != "" {
+			t.Errorf("%s: ships checksum %q for an unversioned URL", name, script.SHA256)
+		}
+	}
+}
+
+func TestResolveBootstrapScript(t *testing.T) {
+	const sum = "0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+	tests := []struct {
+		name       string
+		opts       BootstrapOptions
+		env        map[string]string
+		wantSource string
+		wantSHA256 string
+		wantErr    string
+	}{
+		{
+			name:    "unknown manager",
+			opts:    BootstrapOptions{Allow: true, Manager: "npm"},
+			wantErr: "unknown package manager",
+		},
+		{
+			name:    "default source without a checksum",
+			opts:    BootstrapOptions{Allow: true, Manager: "scoop"},
+			wantErr: "no pinned SHA-256 for " + BootstrapScripts["scoop"].Source,
+		},
+		{
+			name:       "default source with a checksum",
+			opts:       BootstrapOptions{Allow: true, Manager: "brew", SHA256: sum},
+			wantSource: BootstrapScripts["brew"].Source,
+			wantSHA256: sum,
+		},
+		{
+			name:       "script and checksum flags",
+			opts:       BootstrapOptions{Allow: true, Manager: "choco", Source: `\\fileserver\choco.ps1`, SHA256: " " + strings.ToUpper(sum) + " "},
+			wantSource: `\\fileserver\choco.ps1`,
+			wantSHA256: sum,
+		},
+		{
+			name:    "script flag without a checksum",
+			opts:    BootstrapOptions{Allow: true, Manager: "scoop", Source: "/srv/scoop.ps1"},
+			wantErr: "no pinned SHA-256 for /srv/scoop.ps1",
+		},
+		{
+			name:       "environment overrides",
+			opts:       BootstrapOptions{Allow: true, Manager: "brew"},
+			env:        map[string]string{"DEVSTATION_BOOTSTRAP_SCRIPT": "/srv/brew.sh", "DEVSTATION_BOOTSTRAP_SHA256": sum},
+			wantSource: "/srv/brew.sh",
+			wantSHA256: sum,
+		},
+		{
+			name:       "flags win over the environment",
+			opts:       BootstrapOptions{Allow: true, Manager: "brew", Source: "/srv/flag.sh", SHA256: sum},
+			env:        map[string]string{"DEVSTATION_BOOTSTRAP_SCRIPT": "/srv/env.sh", "DEVSTATION_BOOTSTRAP_SHA256": "other"},
+			wantSource: "/srv/flag.sh",
+			wantSHA256: sum,
+		},
+		{
+			name:    "not allowed",
+			opts:    BootstrapOptions{Manager: "choco", SHA256: sum},
+			wantErr: "--allow-bootstrap to install Chocolatey from " + BootstrapScripts["choco"].Source,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("DEVSTATION_BOOTSTRAP_SCRIPT", tt.env["DEVSTATION_BOOTSTRAP_SCRIPT"])
+			t.Setenv("DEVSTATION_BOOTSTRAP_SHA256", tt.env["DEVSTATION_BOOTSTRAP_SHA256"])
+			script, err := resolveBootstrapScript(tt.opts)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("resolveBootstrapScript() error = %v, want it to contain %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if script.Source != tt.wantSource || script.SHA256 != tt.wantSHA256 {
+				t.Errorf("resolveBootstrapScript() = %s %s, want %s %s", script.Source, script.SHA256, tt.wantSource, tt.wantSHA256)
+			}
+		})
+	}
+}
+
+func TestRunBootstrapScript(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the test installer is a shell script")
+	}
+	marker := filepath.Join(t.TempDir(), "installed")
+	content := []byte("touch '" + marker + "'\n")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/install.sh" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write(content)
+	}))
+	defer server.Close()
+
+	sum := sha256.Sum256(content)
+	checksum := hex.EncodeToString(sum[:])
+	other := sha256.Sum256([]byte("something else"))
+	tests := []struct {
+		name      string
+		source    string
+		sha256    string
+		wantErr   string
+		installed bool
+	}{
+		{"verified", server.URL + "/install.sh", checksum, "", true},
+		{"checksum mismatch", server.URL + "/install.sh", hex.EncodeToString(other[:]), "checksum mismatch", false},
+		{"missing script", server.URL + "/missing.sh", checksum, "404", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Remove(marker)
+			script := BootstrapScript{Manager: "Test", Command: "sh", Source: tt.source, SHA256: tt.sha256, Run: []string{"/bin/sh"}}
+			err := runBootstrapScript(script)
+			if tt.wantErr == "" && err != nil {
+				t.Fatal(err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Fatalf("runBootstrapScript() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+			if _, err := os.Stat(marker); (err == nil) != tt.installed {
+				t.Errorf("installer ran = %v, want %v", err == nil, tt.installed)
+			}
+		})
+	}
+}
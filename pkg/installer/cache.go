@@ -0,0 +1,228 @@
+package installer
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// CachingManager is implemented by package managers that can download a package into a
+// local directory and later install it from there without network access
+type CachingManager interface {
+	Download(packageName, dir string) (string, error)
+	InstallArtifact(packageName, artifact string) error
+}
+
+// ManagerName returns the command name of a package manager, as recorded in caches
+func ManagerName(pm PackageManager) string {
+	switch pm.(type) {
+	case *WingetManager:
+		return "winget"
+	case *ChocoManager:
+		return "choco"
+	case *ScoopManager:
+		return "scoop"
+	case *BrewManager:
+		return "brew"
+	case *AptManager:
+		return "apt"
+	}
+	return "unknown"
+}
+
+// chocoRepository is the feed .nupkg files are downloaded from; a version may be appended
+var chocoRepository = "https://community.chocolatey.org/api/v2/package/"
+
+// nuspec holds the parts of a package's .nuspec needed to cache it
+type nuspec struct {
+	Metadata struct {
+		ID           string          `xml:"id"`
+		Version      string          `xml:"version"`
+		Dependencies []nuspecDepends `xml:"dependencies>dependency"`
+		Groups       []struct {
+			Dependencies []nuspecDepends `xml:"dependency"`
+		} `xml:"dependencies>group"`
+	} `xml:"metadata"`
+}
+
+type nuspecDepends struct {
+	ID      string `xml:"id,attr"`
+	Version string `xml:"version,attr"`
+}
+
+// Download fetches the package's .nupkg from the Chocolatey community repository, along with
+// the .nupkg of every package it depends on, so that choco can install it from the directory.
+// The files are named <id>.<version>.nupkg, as in a local Chocolatey feed
+func (c *ChocoManager) Download(packageName, dir string) (string, error) {
+	fmt.Printf("Downloading %s from the Chocolatey repository...\n", packageName)
+
+	client, err := httpClient(5 * time.Minute)
+	if err != nil {
+		return "", err
+	}
+
+	artifact, spec, err := downloadNupkg(client, packageName, "", dir)
+	if err != nil {
+		return "", err
+	}
+
+	seen := map[string]bool{strings.ToLower(spec.Metadata.ID): true}
+	pending := nuspecDependencies(spec)
+	for len(pending) > 0 {
+		dep := pending[0]
+		pending = pending[1:]
+		if seen[strings.ToLower(dep.ID)] {
+			continue
+		}
+		seen[strings.ToLower(dep.ID)] = true
+
+		fmt.Printf("Downloading dependency %s of %s...\n", dep.ID, packageName)
+		_, depSpec, err := downloadNupkg(client, dep.ID, pinnedVersion(dep.Version), dir)
+		if err != nil {
+			return "", fmt.Errorf("failed to download dependency %s: %v", dep.ID, err)
+		}
+		pending = append(pending, nuspecDependencies(depSpec)...)
+	}
+	return artifact, nil
+}
+
+// downloadNupkg saves a package (its latest version when version is empty) into dir and
+// returns the file and its .nuspec
+func downloadNupkg(client *http.Client, id, version, dir string) (string, *nuspec, error) {
+	source := chocoRepository + id
+	if version != "" {
+		source += "/" + version
+	}
+	resp, err := client.Get(source)
+	if err != nil {
+		return "", nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", nil, fmt.Errorf("unexpected HTTP status %s", resp.Status)
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", nil, err
+	}
+
+	spec, err := readNuspec(data)
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid package %s: %v", id, err)
+	}
+
+	artifact := filepath.Join(dir, spec.Metadata.ID+"."+spec.Metadata.Version+".nupkg")
+	if err := os.WriteFile(artifact, data, 0644); err != nil {
+		return "", nil, err
+	}
+	return artifact, spec, nil
+}
+
+// readNuspec reads the .nuspec at the root of a .nupkg archive
+func readNuspec(nupkg []byte) (*nuspec, error) {
+	archive, err := zip.NewReader(bytes.NewReader(nupkg), int64(len(nupkg)))
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range archive.File {
+		if strings.Contains(f.Name, "/") || !strings.EqualFold(filepath.Ext(f.Name), ".nuspec") {
+			continue
+		}
+		r, err := f.Open()
+		if err != nil {
+			return nil, err
+		}
+		defer r.Close()
+
+		var spec nuspec
+		if err := xml.NewDecoder(r).Decode(&spec); err != nil {
+			return nil, fmt.Errorf("invalid %s: %v", f.Name, err)
+		}
+		if spec.Metadata.ID == "" || spec.Metadata.Version == "" {
+			return nil, fmt.Errorf("%s has no id or version", f.Name)
+		}
+		return &spec, nil
+	}
+	return nil, fmt.Errorf("no .nuspec found")
+}
+
+// nuspecDependencies lists a package's dependencies, including those grouped by framework
+func nuspecDependencies(spec *nuspec) []nuspecDepends {
+	deps := append([]nuspecDepends{}, spec.Metadata.Dependencies...)
+	for _, group := range spec.Metadata.Groups {
+		deps = append(deps, group.Dependencies...)
+	}
+	return deps
+}
+
+// pinnedVersion returns the version of an exact dependency range such as [1.2.3]. For other
+// ranges it returns "" and the latest release is cached: Chocolatey packages declare minimum
+// versions, which it satisfies
+func pinnedVersion(versionRange string) string {
+	v := strings.TrimSpace(versionRange)
+	if strings.HasPrefix(v, "[") && strings.HasSuffix(v, "]") && !strings.Contains(v, ",") {
+		return strings.TrimSpace(v[1 : len(v)-1])
+	}
+	return ""
+}
+
+// InstallArtifact installs a package from the directory holding its cached .nupkg
+func (c *ChocoManager) InstallArtifact(packageName, artifact string) error {
+	if !IsElevated() {
+		return elevationRequiredError("Chocolatey")
+	}
+
+	fmt.Printf("Installing %s from local cache via Chocolatey...\n", packageName)
+	cmd := exec.Command("choco", "install", packageName, "-y", "--source", filepath.Dir(artifact))
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+// Download fetches the package's .deb archive with apt-get download
+func (a *AptManager) Download(packageName, dir string) (string, error) {
+	fmt.Printf("Downloading %s via apt...\n", packageName)
+
+	name := aptPackageName(packageName)
+	cmd := managerCommand("apt-get", append(aptNetworkArgs(), "download", name)...)
+	cmd.Dir = dir
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return "", err
+	}
+
+	matches, _ := filepath.Glob(filepath.Join(dir, name+"_*.deb"))
+	if len(matches) == 0 {
+		return "", fmt.Errorf("apt-get download produced no archive for %s", name)
+	}
+	return matches[len(matches)-1], nil
+}
+
+// InstallArtifact installs a package from its cached .deb archive
+func (a *AptManager) InstallArtifact(packageName, artifact string) error {
+	path, err := filepath.Abs(artifact)
+	if err != nil {
+		return err
+	}
+
+	cmd, err := elevatedCommand("apt", "apt-get", "install", "-y", "--no-download", path)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Installing %s from local cache via apt...\n", packageName)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Stdin = os.Stdin
+	return cmd.Run()
+}
@@ -0,0 +1,130 @@
+package installer
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// testNupkg builds a .nupkg whose .nuspec declares the given dependencies
+func testNupkg(t *testing.T, id, version, dependencies string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	w, err := archive.Create(strings.ToLower(id) + ".nuspec")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fmt.Fprintf(w, `<?xml version="1.0"?>
+<package xmlns="http://schemas.microsoft.com/packaging/2015/06/nuspec.xsd">
+  <metadata><id>%s</id><version>%s</version><dependencies>%s</dependencies></metadata>
+</package>`, id, version, dependencies)
+	if _, err := archive.Create("tools/chocolateyInstall.ps1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := archive.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestChocoDownload(t *testing.T) {
+	packages := map[string][]byte{
+		"/cmake": testNupkg(t, "cmake", "3.29.2",
+			`<dependency id="cmake.install" version="[3.29.2]" /><dependency id="chocolatey-core.extension" version="1.3.3" />`),
+		"/cmake.install/3.29.2": testNupkg(t, "cmake.install", "3.29.2",
+			`<group targetFramework=".NETFramework4.0"><dependency id="Chocolatey-Core.Extension" version="1.1" /></group>`),
+		"/chocolatey-core.extension": testNupkg(t, "chocolatey-core.extension", "1.4.0", ""),
+		"/broken":                    []byte("not a zip archive"),
+		"/orphan":                    testNupkg(t, "orphan", "1.0", `<dependency id="missing" />`),
+	}
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL.Path)
+		data, ok := packages[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write(data)
+	}))
+	defer server.Close()
+
+	saved := chocoRepository
+	chocoRepository = server.URL + "/"
+	defer func() { chocoRepository = saved }()
+
+	t.Run("dependency closure", func(t *testing.T) {
+		requests = nil
+		dir := t.TempDir()
+		artifact, err := (&ChocoManager{}).Download("cmake", dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := filepath.Join(dir, "cmake.3.29.2.nupkg"); artifact != want {
+			t.Errorf("Download() = %q, want %q", artifact, want)
+		}
+
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var files []string
+		for _, e := range entries {
+			files = append(files, e.Name())
+		}
+		sort.Strings(files)
+		want := []string{"chocolatey-core.extension.1.4.0.nupkg", "cmake.3.29.2.nupkg", "cmake.install.3.29.2.nupkg"}
+		if !reflect.DeepEqual(files, want) {
+			t.Errorf("cached files = %v, want %v", files, want)
+		}
+		// The extension is required twice, with different casing, but only fetched once
+		if len(requests) != 3 {
+			t.Errorf("requests = %v, want one per package", requests)
+		}
+	})
+
+	errorTests := []struct {
+		pkg  string
+		want string
+	}{
+		{"missing", "404"},
+		{"broken", "invalid package broken"},
+		{"orphan", "failed to download dependency missing"},
+	}
+	for _, tt := range errorTests {
+		t.Run(tt.pkg, func(t *testing.T) {
+			_, err := (&ChocoManager{}).Download(tt.pkg, t.TempDir())
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Download(%q) error = %v, want it to contain %q", tt.pkg, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestPinnedVersion(t *testing.T) {
+	tests := []struct {
+		versionRange string
+		want         string
+	}{
+		{"[1.2.3]", "1.2.3"},
+		{" [ 1.2.3 ] ", "1.2.3"},
+		{"1.2.3", ""},
+		{"", ""},
+		{"[1.0,2.0)", ""},
+		{"(,2.0]", ""},
+	}
+	for _, tt := range tests {
+		if got := pinnedVersion(tt.versionRange); got != tt.want {
+			t.Errorf("pinnedVersion(%q) = %q, want %q", tt.versionRange, got, tt.want)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package installer
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
@@ -12,14 +13,27 @@ type PackageManager interface {
 	Install(packageName string) error
 	IsInstalled(packageName string) bool
 	Update(packageName string) error
+	Outdated() ([]PackageVersion, error)
+	RequiresElevation() bool
+}
+
+// PackageVersion describes an installed package and the newest version available for it
+type PackageVersion struct {
+	Name      string
+	Current   string
+	Available string
 }
 
 // ChocoManager implements PackageManager for Chocolatey
 type ChocoManager struct{}
 
 func (c *ChocoManager) Install(packageName string) error {
+	if !IsElevated() {
+		return elevationRequiredError("Chocolatey")
+	}
+	
 	fmt.Printf("Installing %s via Chocolatey...\n", packageName)
-	cmd := exec.Command("choco", "install", packageName, "-y")
+	cmd := managerCommand("choco", append([]string{"install", packageName, "-y"}, chocoNetworkArgs()...)...)
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 	return cmd.Run()
@@ -35,19 +49,53 @@ func (c *ChocoManager) IsInstalled(packageName string) bool {
 }
 
 func (c *ChocoManager) Update(packageName string) error {
+	if !IsElevated() {
+		return elevationRequiredError("Chocolatey")
+	}
+	
 	fmt.Printf("Updating %s via Chocolatey...\n", packageName)
-	cmd := exec.Command("choco", "upgrade", packageName, "-y")
+	cmd := managerCommand("choco", append([]string{"upgrade", packageName, "-y"}, chocoNetworkArgs()...)...)
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 	return cmd.Run()
 }
 
+// RequiresElevation reports that Chocolatey installs into machine-wide locations
+func (c *ChocoManager) RequiresElevation() bool {
+	return true
+}
+
+// Outdated lists packages with a newer version available via Chocolatey
+func (c *ChocoManager) Outdated() ([]PackageVersion, error) {
+	cmd := exec.Command("choco", "outdated", "--limit-output")
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("choco outdated failed: %v", err)
+	}
+	
+	// Limited output is one "name|current|available|pinned" record per line
+	var versions []PackageVersion
+	for _, line := range strings.Split(string(output), "\n") {
+		fields := strings.Split(strings.TrimSpace(line), "|")
+		if len(fields) < 3 {
+			continue
+		}
+		versions = append(versions, PackageVersion{
+			Name:      fields[0],
+			Current:   fields[1],
+			Available: fields[2],
+		})
+	}
+	
+	return versions, nil
+}
+
 // WingetManager implements PackageManager for Windows Package Manager
 type WingetManager struct{}
 
 func (w *WingetManager) Install(packageName string) error {
 	fmt.Printf("Installing %s via winget...\n", packageName)
-	cmd := exec.Command("winget", "install", packageName, "--accept-package-agreements", "--accept-source-agreements")
+	cmd := managerCommand("winget", "install", packageName, "--accept-package-agreements", "--accept-source-agreements")
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 	return cmd.Run()
@@ -64,44 +112,388 @@ func (w *WingetManager) IsInstalled(packageName string) bool {
 
 func (w *WingetManager) Update(packageName string) error {
 	fmt.Printf("Updating %s via winget...\n", packageName)
-	cmd := exec.Command("winget", "upgrade", packageName)
+	cmd := managerCommand("winget", "upgrade", packageName)
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 	return cmd.Run()
 }
 
-// GetAvailablePackageManager returns the first available package manager
-func GetAvailablePackageManager() PackageManager {
-	// Check for winget first (newer, built-in)
-	if isCommandAvailable("winget") {
-		return &WingetManager{}
+// RequiresElevation reports that winget runs unelevated; installers prompt for UAC themselves
+func (w *WingetManager) RequiresElevation() bool {
+	return false
+}
+
+// Outdated lists packages with a newer version available via winget
+func (w *WingetManager) Outdated() ([]PackageVersion, error) {
+	cmd := exec.Command("winget", "upgrade", "--accept-source-agreements")
+	output, err := cmd.Output()
+	if err != nil && len(output) == 0 {
+		return nil, fmt.Errorf("winget upgrade failed: %v", err)
 	}
 	
-	// Fallback to chocolatey
-	if isCommandAvailable("choco") {
-		return &ChocoManager{}
+	var versions []PackageVersion
+	for _, row := range parseWingetTable(string(output)) {
+		if row["Id"] == "" || row["Available"] == "" {
+			continue
+		}
+		versions = append(versions, PackageVersion{
+			Name:      row["Id"],
+			Current:   row["Version"],
+			Available: row["Available"],
+		})
 	}
 	
-	return nil
+	return versions, nil
 }
 
-func isCommandAvailable(command string) bool {
-	_, err := exec.LookPath(command)
-	return err == nil
+// parseWingetTable parses winget's fixed-width table output into rows keyed by column header
+func parseWingetTable(output string) []map[string]string {
+	lines := strings.Split(strings.ReplaceAll(output, "\r", "\n"), "\n")
+	
+	// Locate the header line; winget prints progress spinners before it
+	header := -1
+	for i, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), "Name") && strings.Contains(line, " Id ") {
+			header = i
+			break
+		}
+	}
+	if header < 0 {
+		return nil
+	}
+	
+	headerRunes := []rune(lines[header])
+	var names []string
+	var starts []int
+	for i, r := range headerRunes {
+		if r != ' ' && (i == 0 || headerRunes[i-1] == ' ') {
+			starts = append(starts, i)
+			end := i
+			for end < len(headerRunes) && headerRunes[end] != ' ' {
+				end++
+			}
+			names = append(names, string(headerRunes[i:end]))
+		}
+	}
+	
+	var rows []map[string]string
+	for _, line := range lines[header+1:] {
+		runes := []rune(line)
+		if len(strings.Trim(line, "- ")) == 0 || len(runes) < starts[len(starts)-1] {
+			continue
+		}
+		row := make(map[string]string, len(names))
+		for i, name := range names {
+			end := len(runes)
+			if i+1 < len(starts) && starts[i+1] < end {
+				end = starts[i+1]
+			}
+			if starts[i] < end {
+				row[name] = strings.TrimSpace(string(runes[starts[i]:end]))
+			}
+		}
+		rows = append(rows, row)
+	}
+	
+	return rows
 }
 
-// InstallPackageManager installs Chocolatey if no package manager is available
-func InstallPackageManager() error {
-	if GetAvailablePackageManager() != nil {
-		return nil
+// MatchesPackage reports whether a package name reported by a package manager refers to
+// the given managed package name. Names are compared exactly against the names each manager
+// knows the package by (e.g. winget reports "Kitware.CMake" for "cmake"), so unrelated
+// packages such as Python.Launcher or Git.GCM-Core do not match.
+func MatchesPackage(reported, packageName string) bool {
+	reported = strings.ToLower(reported)
+	for _, name := range []string{packageName, aptPackageName(packageName), brewPackageName(packageName)} {
+		if reported == strings.ToLower(name) {
+			return true
+		}
+	}
+	for _, id := range wingetPackageIDs[packageName] {
+		id = strings.ToLower(id)
+		if reported == id || (strings.HasSuffix(id, ".*") && strings.HasPrefix(reported, strings.TrimSuffix(id, "*"))) {
+			return true
+		}
+	}
+	return false
+}
+
+// wingetPackageIDs maps devstation package names to the winget package IDs that provide them.
+// An ID ending in ".*" matches every ID with that prefix, for packages winget splits by version.
+var wingetPackageIDs = map[string][]string{
+	"python":                     {"Python.Python.3.*"},
+	"git":                        {"Git.Git"},
+	"vscode":                     {"Microsoft.VisualStudioCode"},
+	"cmake":                      {"Kitware.CMake"},
+	"make":                       {"GnuWin32.Make", "ezwinports.make"},
+	"clang-format":               {"LLVM.LLVM"},
+	"visualstudio2022buildtools": {"Microsoft.VisualStudio.2022.BuildTools"},
+}
+
+// ScoopManager implements PackageManager for Scoop, which installs into the user profile
+type ScoopManager struct{}
+
+func (s *ScoopManager) Install(packageName string) error {
+	fmt.Printf("Installing %s via Scoop...\n", packageName)
+	cmd := managerCommand("scoop", "install", packageName)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+func (s *ScoopManager) IsInstalled(packageName string) bool {
+	cmd := exec.Command("scoop", "list", packageName)
+	output, err := cmd.Output()
+	if err != nil {
+		return false
+	}
+	return strings.Contains(string(output), packageName)
+}
+
+func (s *ScoopManager) Update(packageName string) error {
+	fmt.Printf("Updating %s via Scoop...\n", packageName)
+	cmd := managerCommand("scoop", "update", packageName)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+// RequiresElevation reports that Scoop installs are user-scoped
+func (s *ScoopManager) RequiresElevation() bool {
+	return false
+}
+
+// Outdated lists packages with a newer version available via Scoop
+func (s *ScoopManager) Outdated() ([]PackageVersion, error) {
+	cmd := exec.Command("scoop", "status")
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("scoop status failed: %v", err)
+	}
+	
+	// Rows after the dashed separator start with name, installed and latest version
+	var versions []PackageVersion
+	inTable := false
+	for _, line := range strings.Split(string(output), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) > 0 && strings.HasPrefix(fields[0], "----") {
+			inTable = true
+			continue
+		}
+		if !inTable || len(fields) < 3 {
+			continue
+		}
+		versions = append(versions, PackageVersion{
+			Name:      fields[0],
+			Current:   fields[1],
+			Available: fields[2],
+		})
+	}
+	
+	return versions, nil
+}
+
+// BrewManager implements PackageManager for Homebrew on macOS
+type BrewManager struct{}
+
+func (b *BrewManager) Install(packageName string) error {
+	fmt.Printf("Installing %s via Homebrew...\n", packageName)
+	cmd := managerCommand("brew", "install", brewPackageName(packageName))
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+func (b *BrewManager) IsInstalled(packageName string) bool {
+	cmd := exec.Command("brew", "list", "--versions", brewPackageName(packageName))
+	output, err := cmd.Output()
+	if err != nil {
+		return false
+	}
+	return strings.TrimSpace(string(output)) != ""
+}
+
+func (b *BrewManager) Update(packageName string) error {
+	fmt.Printf("Updating %s via Homebrew...\n", packageName)
+	cmd := managerCommand("brew", "upgrade", brewPackageName(packageName))
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+// RequiresElevation reports that Homebrew must run as a normal user
+func (b *BrewManager) RequiresElevation() bool {
+	return false
+}
+
+// Outdated lists formulae and casks with a newer version available via Homebrew
+func (b *BrewManager) Outdated() ([]PackageVersion, error) {
+	cmd := exec.Command("brew", "outdated", "--json=v2")
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("brew outdated failed: %v", err)
+	}
+	
+	type outdatedEntry struct {
+		Name              string   `json:"name"`
+		InstalledVersions []string `json:"installed_versions"`
+		CurrentVersion    string   `json:"current_version"`
+	}
+	var outdated struct {
+		Formulae []outdatedEntry `json:"formulae"`
+		Casks    []outdatedEntry `json:"casks"`
+	}
+	if err := json.Unmarshal(output, &outdated); err != nil {
+		return nil, fmt.Errorf("failed to parse brew output: %v", err)
 	}
 	
-	fmt.Println("No package manager found. Installing Chocolatey...")
-	cmd := exec.Command("powershell", "-Command", 
-		"Set-ExecutionPolicy Bypass -Scope Process -Force; "+
-		"[System.Net.ServicePointManager]::SecurityProtocol = [System.Net.ServicePointManager]::SecurityProtocol -bor 3072; "+
-		"iex ((New-Object System.Net.WebClient).DownloadString('https://community.chocolatey.org/install.ps1'))")
+	var versions []PackageVersion
+	for _, entry := range append(outdated.Formulae, outdated.Casks...) {
+		current := ""
+		if len(entry.InstalledVersions) > 0 {
+			current = entry.InstalledVersions[len(entry.InstalledVersions)-1]
+		}
+		versions = append(versions, PackageVersion{
+			Name:      entry.Name,
+			Current:   current,
+			Available: entry.CurrentVersion,
+		})
+	}
+	
+	return versions, nil
+}
+
+// brewPackageNames maps devstation package names to Homebrew formulae and casks where they differ
+var brewPackageNames = map[string]string{
+	"mingw":                      "mingw-w64",
+	"visualstudio2022buildtools": "gcc",
+	"vscode":                     "visual-studio-code",
+}
+
+func brewPackageName(packageName string) string {
+	if name, ok := brewPackageNames[packageName]; ok {
+		return name
+	}
+	return packageName
+}
+
+// AptManager implements PackageManager for apt on Debian-based Linux distributions
+type AptManager struct{}
+
+func (a *AptManager) Install(packageName string) error {
+	cmd, err := elevatedCommand("apt", "apt-get", append(aptNetworkArgs(), "install", "-y", aptPackageName(packageName))...)
+	if err != nil {
+		return err
+	}
+	
+	fmt.Printf("Installing %s via apt...\n", packageName)
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
+	cmd.Stdin = os.Stdin
 	return cmd.Run()
 }
+
+func (a *AptManager) IsInstalled(packageName string) bool {
+	cmd := exec.Command("dpkg-query", "-W", "-f=${Status}", aptPackageName(packageName))
+	output, err := cmd.Output()
+	if err != nil {
+		return false
+	}
+	return strings.Contains(string(output), "install ok installed")
+}
+
+func (a *AptManager) Update(packageName string) error {
+	cmd, err := elevatedCommand("apt", "apt-get", append(aptNetworkArgs(), "install", "--only-upgrade", "-y", aptPackageName(packageName))...)
+	if err != nil {
+		return err
+	}
+	
+	fmt.Printf("Updating %s via apt...\n", packageName)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Stdin = os.Stdin
+	return cmd.Run()
+}
+
+// RequiresElevation reports that apt needs root to modify system packages
+func (a *AptManager) RequiresElevation() bool {
+	return true
+}
+
+// Outdated lists packages with a newer version available via apt
+func (a *AptManager) Outdated() ([]PackageVersion, error) {
+	cmd := exec.Command("apt", "list", "--upgradable")
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("apt list failed: %v", err)
+	}
+	
+	// Lines look like "cmake/jammy-updates 3.22.1-1ubuntu1.2 amd64 [upgradable from: 3.22.1-1ubuntu1.1]"
+	var versions []PackageVersion
+	for _, line := range strings.Split(string(output), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 6 || !strings.Contains(fields[0], "/") {
+			continue
+		}
+		name := strings.SplitN(fields[0], "/", 2)[0]
+		versions = append(versions, PackageVersion{
+			Name:      name,
+			Current:   strings.TrimSuffix(fields[len(fields)-1], "]"),
+			Available: fields[1],
+		})
+	}
+	
+	return versions, nil
+}
+
+// aptPackageNames maps devstation package names to Debian package names where they differ
+var aptPackageNames = map[string]string{
+	"python":                     "python3",
+	"mingw":                      "gcc",
+	"visualstudio2022buildtools": "build-essential",
+	"vscode":                     "code",
+}
+
+func aptPackageName(packageName string) string {
+	if name, ok := aptPackageNames[packageName]; ok {
+		return name
+	}
+	return packageName
+}
+
+// GetAvailablePackageManager returns the first available package manager that can run
+// with the current privileges, preferring user-scoped managers when elevation is unavailable
+func GetAvailablePackageManager() PackageManager {
+	candidates := []struct {
+		command string
+		manager PackageManager
+	}{
+		{"winget", &WingetManager{}}, // Newer, built-in
+		{"choco", &ChocoManager{}},
+		{"scoop", &ScoopManager{}}, // User-scoped
+		{"brew", &BrewManager{}},
+		{"apt-get", &AptManager{}},
+	}
+	
+	var fallback PackageManager
+	for _, candidate := range candidates {
+		if !isCommandAvailable(candidate.command) {
+			continue
+		}
+		if candidate.manager.RequiresElevation() && !CanElevate() {
+			// Keep it so callers get a clear elevation error if nothing else is usable
+			if fallback == nil {
+				fallback = candidate.manager
+			}
+			continue
+		}
+		return candidate.manager
+	}
+	
+	return fallback
+}
+
+func isCommandAvailable(command string) bool {
+	_, err := exec.LookPath(command)
+	return err == nil
+}
@@ -0,0 +1,48 @@
+package installer
+
+import "testing"
+
+func TestMatchesPackage(t *testing.T) {
+	tests := []struct {
+		reported string
+		pkg      string
+		want     bool
+	}{
+		// Package managers that use devstation's names
+		{"cmake", "cmake", true},
+		{"CMake", "cmake", true},
+		{"git", "git", true},
+		{"gitk", "git", false},
+
+		// apt and Homebrew names
+		{"python3", "python", true},
+		{"build-essential", "visualstudio2022buildtools", true},
+		{"code", "vscode", true},
+		{"visual-studio-code", "vscode", true},
+		{"mingw-w64", "mingw", true},
+		{"python3-pip", "python", false},
+
+		// winget IDs
+		{"Kitware.CMake", "cmake", true},
+		{"Git.Git", "git", true},
+		{"Microsoft.VisualStudioCode", "vscode", true},
+		{"Python.Python.3.12", "python", true},
+		{"python.python.3.11", "python", true},
+		{"LLVM.LLVM", "clang-format", true},
+		{"Microsoft.VisualStudio.2022.BuildTools", "visualstudio2022buildtools", true},
+		{"Python.Launcher", "python", false},
+		{"Python.Python.2", "python", false},
+		{"Git.GCM-Core", "git", false},
+		{"Microsoft.VisualStudioCode.Insiders", "vscode", false},
+		{"Kitware.CMake", "make", false},
+		{"GnuWin32.Make", "make", true},
+		{"Microsoft.VisualStudio.2022.Community", "visualstudio2022buildtools", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.reported+"/"+tt.pkg, func(t *testing.T) {
+			if got := MatchesPackage(tt.reported, tt.pkg); got != tt.want {
+				t.Errorf("MatchesPackage(%q, %q) = %v, want %v", tt.reported, tt.pkg, got, tt.want)
+			}
+		})
+	}
+}
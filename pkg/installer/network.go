@@ -0,0 +1,88 @@
+package installer
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"time"
+
+	"devstation-cli/pkg/config"
+)
+
+// managerCommand builds a package manager command whose environment carries the
+// configured proxy and CA bundle, for managers that read them from the environment
+func managerCommand(name string, args ...string) *exec.Cmd {
+	cmd := exec.Command(name, args...)
+	cfg := config.Current()
+
+	var env []string
+	if cfg.Proxy != "" {
+		env = append(env, "HTTP_PROXY="+cfg.Proxy, "HTTPS_PROXY="+cfg.Proxy, "http_proxy="+cfg.Proxy, "https_proxy="+cfg.Proxy)
+	}
+	if cfg.CABundle != "" {
+		env = append(env, "SSL_CERT_FILE="+cfg.CABundle, "CURL_CA_BUNDLE="+cfg.CABundle)
+	}
+	if len(env) > 0 {
+		cmd.Env = append(os.Environ(), env...)
+	}
+	return cmd
+}
+
+// chocoNetworkArgs returns the Chocolatey arguments for the configured proxy
+func chocoNetworkArgs() []string {
+	if proxy := config.Current().Proxy; proxy != "" {
+		return []string{"--proxy=" + proxy}
+	}
+	return nil
+}
+
+// aptNetworkArgs returns the apt options for the configured proxy and CA bundle; they are
+// passed as options because sudo does not preserve the proxy environment
+func aptNetworkArgs() []string {
+	cfg := config.Current()
+
+	var args []string
+	if cfg.Proxy != "" {
+		args = append(args, "-o", "Acquire::http::Proxy="+cfg.Proxy, "-o", "Acquire::https::Proxy="+cfg.Proxy)
+	}
+	if cfg.CABundle != "" {
+		args = append(args, "-o", "Acquire::https::CaInfo="+cfg.CABundle)
+	}
+	return args
+}
+
+// httpClient returns an HTTP client that uses the configured proxy and trusts the
+// configured CA bundle in addition to the system roots
+func httpClient(timeout time.Duration) (*http.Client, error) {
+	cfg := config.Current()
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	if cfg.Proxy != "" {
+		proxyURL, err := url.Parse(cfg.Proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy URL %q: %v", cfg.Proxy, err)
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	if cfg.CABundle != "" {
+		pem, err := os.ReadFile(cfg.CABundle)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA bundle: %v", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in CA bundle %s", cfg.CABundle)
+		}
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool}
+	}
+
+	return &http.Client{Timeout: timeout, Transport: transport}, nil
+}
@@ -0,0 +1,55 @@
+package installer
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"sync"
+)
+
+// sudoNotice ensures the sudo explanation is only printed once per run
+var sudoNotice sync.Once
+
+// IsElevated reports whether devstation is running with administrator (Windows) or root (Unix) rights
+func IsElevated() bool {
+	return isElevated()
+}
+
+// IsRoot reports whether devstation is running as the Unix root user
+func IsRoot() bool {
+	return runtime.GOOS != "windows" && os.Geteuid() == 0
+}
+
+// CanElevate reports whether commands that need elevation can be run, either because
+// devstation is already elevated or because sudo is available to re-invoke them
+func CanElevate() bool {
+	if IsElevated() {
+		return true
+	}
+	return runtime.GOOS != "windows" && isCommandAvailable("sudo")
+}
+
+// elevationRequiredError builds the error returned when a backend needs elevation that is unavailable
+func elevationRequiredError(manager string) error {
+	if runtime.GOOS == "windows" {
+		return fmt.Errorf("%s requires administrator rights; re-run devstation from an elevated PowerShell, or install Scoop for user-scoped installs", manager)
+	}
+	return fmt.Errorf("%s requires root; re-run devstation with sudo or install sudo", manager)
+}
+
+// elevatedCommand builds a command that runs with elevated rights, re-invoking it via sudo
+// on Unix when devstation itself is not running as root
+func elevatedCommand(manager, name string, args ...string) (*exec.Cmd, error) {
+	if IsElevated() {
+		return exec.Command(name, args...), nil
+	}
+	if !CanElevate() {
+		return nil, elevationRequiredError(manager)
+	}
+	
+	sudoNotice.Do(func() {
+		fmt.Printf("%s needs root privileges; running it via sudo (you may be asked for your password)\n", manager)
+	})
+	return exec.Command("sudo", append([]string{name}, args...)...), nil
+}
@@ -0,0 +1,10 @@
+//go:build !windows
+
+package installer
+
+import "os"
+
+// isElevated reports whether the process runs as root
+func isElevated() bool {
+	return os.Geteuid() == 0
+}
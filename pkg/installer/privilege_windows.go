@@ -0,0 +1,26 @@
+//go:build windows
+
+package installer
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+// isElevated reports whether the process token is elevated (run as Administrator)
+func isElevated() bool {
+	token, err := syscall.OpenCurrentProcessToken()
+	if err != nil {
+		return false
+	}
+	defer token.Close()
+	
+	var elevation uint32
+	var returned uint32
+	err = syscall.GetTokenInformation(token, syscall.TokenElevation,
+		(*byte)(unsafe.Pointer(&elevation)), uint32(unsafe.Sizeof(elevation)), &returned)
+	if err != nil {
+		return false
+	}
+	return elevation != 0
+}
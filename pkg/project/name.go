@@ -0,0 +1,92 @@
+package project
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Name is a validated project name with the identifiers scaffolds derive from it
+type Name struct {
+	Name         string // As given; names the project directory
+	CIdentifier  string // C source/header base name, e.g. "my_lib"
+	HeaderGuard  string // C include guard, e.g. "MY_LIB_H"
+	ImportName   string // Python package name, e.g. "my_lib"
+	Distribution string // Normalized Python distribution name, e.g. "my-lib"
+}
+
+var (
+	// validName follows the PEP 508 project name rule, which also keeps the name a single,
+	// portable directory name: no separators, spaces, ".." or characters Windows forbids
+	validName          = regexp.MustCompile(`^[A-Za-z0-9]([A-Za-z0-9._-]*[A-Za-z0-9])?$`)
+	nonIdentifierChars = regexp.MustCompile(`[^a-z0-9_]+`)
+	distributionSeps   = regexp.MustCompile(`[-_.]+`)
+)
+
+// windowsReserved lists the device names Windows refuses as file names, with or without an extension
+var windowsReserved = map[string]bool{
+	"CON": true, "PRN": true, "AUX": true, "NUL": true,
+	"COM1": true, "COM2": true, "COM3": true, "COM4": true, "COM5": true, "COM6": true, "COM7": true, "COM8": true, "COM9": true,
+	"LPT1": true, "LPT2": true, "LPT3": true, "LPT4": true, "LPT5": true, "LPT6": true, "LPT7": true, "LPT8": true, "LPT9": true,
+}
+
+// ParseName validates a project name and derives its identifiers. Names must be a single
+// directory name that works on every platform, so paths, traversal and reserved device
+// names are rejected rather than normalized.
+func ParseName(name string) (Name, error) {
+	switch {
+	case name == "":
+		return Name{}, fmt.Errorf("project name is empty")
+	case name == "." || name == ".." || strings.ContainsAny(name, `/\`):
+		return Name{}, fmt.Errorf("invalid project name %q: must be a plain name, not a path", name)
+	case !validName.MatchString(name):
+		return Name{}, fmt.Errorf("invalid project name %q: use letters, digits, '.', '_' and '-', starting and ending with a letter or digit", name)
+	}
+	if device := strings.ToUpper(strings.SplitN(name, ".", 2)[0]); windowsReserved[device] {
+		return Name{}, fmt.Errorf("invalid project name %q: %s is a reserved device name on Windows", name, device)
+	}
+
+	identifier := CIdentifier(name)
+	return Name{
+		Name:         name,
+		CIdentifier:  identifier,
+		HeaderGuard:  strings.ToUpper(identifier) + "_H",
+		ImportName:   ImportName(name),
+		Distribution: DistributionName(name),
+	}, nil
+}
+
+// identifier lowercases a name and replaces runs of characters that cannot appear in an
+// identifier with underscores, prefixing an underscore when it would start with a digit
+func identifier(name string) string {
+	id := nonIdentifierChars.ReplaceAllString(strings.ToLower(name), "_")
+	id = strings.Trim(id, "_")
+	if id == "" {
+		return ""
+	}
+	if id[0] >= '0' && id[0] <= '9' {
+		id = "_" + id
+	}
+	return id
+}
+
+// CIdentifier derives a C identifier from a project name, e.g. "my-lib" -> "my_lib"
+func CIdentifier(projectName string) string {
+	if id := identifier(projectName); id != "" {
+		return id
+	}
+	return "project"
+}
+
+// ImportName derives a valid Python import name from a project name, e.g. "My-Project" -> "my_project"
+func ImportName(projectName string) string {
+	if id := identifier(projectName); id != "" {
+		return id
+	}
+	return "app"
+}
+
+// DistributionName derives a normalized distribution name from a project name, e.g. "My_Project" -> "my-project"
+func DistributionName(projectName string) string {
+	return distributionSeps.ReplaceAllString(strings.ToLower(strings.TrimSpace(projectName)), "-")
+}
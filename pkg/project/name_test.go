@@ -0,0 +1,68 @@
+package project
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseName(t *testing.T) {
+	tests := []struct {
+		name string
+		want Name
+	}{
+		{"my-lib", Name{"my-lib", "my_lib", "MY_LIB_H", "my_lib", "my-lib"}},
+		{"My_Project", Name{"My_Project", "my_project", "MY_PROJECT_H", "my_project", "my-project"}},
+		{"data.tools", Name{"data.tools", "data_tools", "DATA_TOOLS_H", "data_tools", "data-tools"}},
+		{"a--b__c", Name{"a--b__c", "a_b__c", "A_B__C_H", "a_b__c", "a-b-c"}},
+		{"2d-engine", Name{"2d-engine", "_2d_engine", "_2D_ENGINE_H", "_2d_engine", "2d-engine"}},
+		{"42", Name{"42", "_42", "_42_H", "_42", "42"}},
+		{"x", Name{"x", "x", "X_H", "x", "x"}},
+		{"console", Name{"console", "console", "CONSOLE_H", "console", "console"}},
+		{"com10", Name{"com10", "com10", "COM10_H", "com10", "com10"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseName(tt.name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseName(%q) = %+v, want %+v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseNameInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"", "empty"},
+		{".", "not a path"},
+		{"..", "not a path"},
+		{"../app", "not a path"},
+		{"apps/app", "not a path"},
+		{`apps\app`, "not a path"},
+		{"/app", "not a path"},
+		{"my app", "use letters"},
+		{"-app", "use letters"},
+		{"app.", "use letters"},
+		{"_app", "use letters"},
+		{"app:1", "use letters"},
+		{"CON", "reserved device name"},
+		{"con", "reserved device name"},
+		{"con.txt", "reserved device name"},
+		{"Nul.tar.gz", "reserved device name"},
+		{"LPT9", "reserved device name"},
+		{"com1", "reserved device name"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseName(tt.name)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("ParseName(%q) error = %v, want it to contain %q", tt.name, err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,168 @@
+package project
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os/exec"
+	"os/user"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// TemplateExt marks the files rendered into a scaffold; the suffix is dropped from the output path
+const TemplateExt = ".tmpl"
+
+// Data is the model every scaffold template is rendered with, e.g. {{.ImportName}}
+type Data struct {
+	Name         string            // Project name as given
+	CIdentifier  string            // See Name
+	HeaderGuard  string            // See Name
+	ImportName   string            // See Name
+	Distribution string            // See Name
+	Author       string            // Project author; empty when unknown
+	License      string            // SPDX license identifier, e.g. "MIT"; empty for none
+	Year         int               // Year the project was created
+	Vars         map[string]string // Variables of a user-defined template, e.g. {{.Vars.team}}
+	Options      interface{}       // Options of the project type, e.g. python.TemplateOptions
+}
+
+// Options are the scaffolding choices shared by every project type
+type Options struct {
+	Mode    Mode   // How to treat an existing, non-empty project directory
+	Author  string // Defaults to DefaultAuthor()
+	License string // SPDX license identifier; no license when empty
+
+	UserTemplate *Template         // Rendered over the built-in scaffold; nil for none
+	Vars         map[string]string // Values of the user template's variables
+}
+
+// NewData builds the template data for a project
+func NewData(name Name, opts Options, options interface{}) Data {
+	author := opts.Author
+	if author == "" {
+		author = DefaultAuthor()
+	}
+	return Data{
+		Name:         name.Name,
+		CIdentifier:  name.CIdentifier,
+		HeaderGuard:  name.HeaderGuard,
+		ImportName:   name.ImportName,
+		Distribution: name.Distribution,
+		Author:       author,
+		License:      opts.License,
+		Year:         time.Now().Year(),
+		Vars:         opts.Vars,
+		Options:      options,
+	}
+}
+
+// DefaultAuthor returns git's user.name, falling back to the login name
+func DefaultAuthor() string {
+	if output, err := exec.Command("git", "config", "--get", "user.name").Output(); err == nil {
+		if name := strings.TrimSpace(string(output)); name != "" {
+			return name
+		}
+	}
+	if u, err := user.Current(); err == nil {
+		if u.Name != "" {
+			return u.Name
+		}
+		return u.Username
+	}
+	return ""
+}
+
+// funcs are available in every scaffold template
+var funcs = template.FuncMap{
+	"join":    strings.Join,
+	"replace": strings.ReplaceAll,
+	"upper":   strings.ToUpper,
+	"lower":   strings.ToLower,
+	"quote":   strconv.Quote,
+}
+
+// Render renders the templates under dir in fsys into file contents keyed by their path
+// relative to dir, with TemplateExt removed. Paths are templates too, so
+// "src/{{.CIdentifier}}.c.tmpl" renders to "src/my_lib.c". A missing dir renders nothing.
+// extra adds functions for the project type's templates.
+func Render(fsys fs.FS, dir string, data Data, extra template.FuncMap) (map[string]string, error) {
+	files := make(map[string]string)
+	err := fs.WalkDir(fsys, dir, func(name string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() || !strings.HasSuffix(name, TemplateExt) {
+			return nil
+		}
+
+		content, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return err
+		}
+		rel := strings.TrimSuffix(strings.TrimPrefix(name, dir+"/"), TemplateExt)
+		target, err := renderString(name+" (path)", rel, data, extra)
+		if err != nil {
+			return err
+		}
+		// Rendered paths must stay inside the project whatever the data contains
+		if clean := path.Clean(target); path.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, "../") {
+			return fmt.Errorf("template %s renders to %s, outside the project", name, target)
+		}
+		rendered, err := renderString(name, string(content), data, extra)
+		if err != nil {
+			return err
+		}
+		files[filepath.FromSlash(target)] = rendered
+		return nil
+	})
+	if errors.Is(err, fs.ErrNotExist) {
+		return files, nil
+	}
+	return files, err
+}
+
+// renderString executes one template; unknown fields are errors rather than "<no value>"
+func renderString(name, text string, data Data, extra template.FuncMap) (string, error) {
+	tmpl, err := template.New(name).Option("missingkey=error").Funcs(funcs).Funcs(extra).Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("invalid template %s: %v", name, err)
+	}
+	var b bytes.Buffer
+	if err := tmpl.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("failed to render %s: %v", name, err)
+	}
+	return b.String(), nil
+}
+
+//go:embed licenses
+var licenses embed.FS
+
+// Licenses returns the SPDX identifiers whose license text devstation can generate
+func Licenses() []string {
+	entries, _ := licenses.ReadDir("licenses")
+	var ids []string
+	for _, entry := range entries {
+		ids = append(ids, strings.TrimSuffix(entry.Name(), TemplateExt))
+	}
+	return ids
+}
+
+// LicenseFile renders the LICENSE file for the project's license. It returns no content for
+// projects without a license, and an error for licenses whose text is not bundled.
+func LicenseFile(data Data) (string, error) {
+	if data.License == "" {
+		return "", nil
+	}
+	text, err := licenses.ReadFile(path.Join("licenses", data.License+TemplateExt))
+	if err != nil {
+		return "", fmt.Errorf("no license text for %q; expected one of %s", data.License, strings.Join(Licenses(), ", "))
+	}
+	return renderString(data.License, string(text), data, nil)
+}
@@ -0,0 +1,262 @@
+package project
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Mode controls how scaffolding treats a target directory that already contains files
+type Mode string
+
+const (
+	ModeAbort       Mode = ""            // Refuse to scaffold into a non-empty directory
+	ModeForce       Mode = "force"       // Overwrite files that differ
+	ModeMerge       Mode = "merge"       // Only write files that do not exist yet
+	ModeInteractive Mode = "interactive" // Show a diff and ask before changing each file
+)
+
+// Action is what scaffolding does to one file
+type Action string
+
+const (
+	ActionCreate    Action = "create"
+	ActionChange    Action = "change"
+	ActionUnchanged Action = "unchanged"
+)
+
+// Change is the planned action for one scaffolded file
+type Change struct {
+	Path    string
+	Action  Action
+	Content string
+}
+
+// Plan compares the files to scaffold with what is on disk, sorted by path
+func Plan(files map[string]string) []Change {
+	var changes []Change
+	for path, content := range files {
+		change := Change{Path: path, Action: ActionCreate, Content: content}
+		if existing, err := os.ReadFile(path); err == nil {
+			change.Action = ActionChange
+			if string(existing) == content {
+				change.Action = ActionUnchanged
+			}
+		}
+		changes = append(changes, change)
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Path < changes[j].Path })
+	return changes
+}
+
+// Scaffold creates the directories and writes the files of a new project under root. A new or
+// empty root is simply filled in; when root already has files, the planned changes are listed
+// and mode decides whether existing files are overwritten, kept or confirmed one by one.
+func Scaffold(root string, dirs []string, files map[string]string, mode Mode) error {
+	nonEmpty, err := hasEntries(root)
+	if err != nil {
+		return err
+	}
+	changes := Plan(files)
+
+	if nonEmpty {
+		fmt.Printf("%s already exists and is not empty:\n", root)
+		printPlan(root, changes)
+		if mode == ModeAbort {
+			return fmt.Errorf("refusing to scaffold over %s; use --merge to only add missing files, --force to overwrite changed files or --interactive to review each change", root)
+		}
+	}
+
+	if err := os.MkdirAll(root, 0755); err != nil {
+		return fmt.Errorf("failed to create project directory: %v", err)
+	}
+	for _, dir := range dirs {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create directory %s: %v", dir, err)
+		}
+	}
+
+	prompt := bufio.NewReader(os.Stdin)
+	for _, change := range changes {
+		switch change.Action {
+		case ActionUnchanged:
+			continue
+		case ActionChange:
+			switch mode {
+			case ModeMerge:
+				continue
+			case ModeInteractive:
+				overwrite, err := confirmChange(prompt, root, change)
+				if err != nil {
+					return err
+				}
+				if !overwrite {
+					continue
+				}
+			}
+		}
+
+		if err := os.MkdirAll(filepath.Dir(change.Path), 0755); err != nil {
+			return fmt.Errorf("failed to create directory %s: %v", filepath.Dir(change.Path), err)
+		}
+		if err := os.WriteFile(change.Path, []byte(change.Content), 0644); err != nil {
+			return fmt.Errorf("failed to create file %s: %v", change.Path, err)
+		}
+	}
+	return nil
+}
+
+// hasEntries reports whether dir exists and contains anything
+func hasEntries(dir string) (bool, error) {
+	info, err := os.Stat(dir)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if !info.IsDir() {
+		return false, fmt.Errorf("%s exists and is not a directory", dir)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return false, err
+	}
+	return len(entries) > 0, nil
+}
+
+// printPlan lists the files scaffolding would create or change
+func printPlan(root string, changes []Change) {
+	symbols := map[Action]string{ActionCreate: "+", ActionChange: "~", ActionUnchanged: "="}
+	for _, change := range changes {
+		fmt.Printf("  %s %-9s %s\n", symbols[change.Action], change.Action, relativePath(root, change.Path))
+	}
+}
+
+// confirmChange shows the diff of a changed file and asks whether to overwrite it
+func confirmChange(prompt *bufio.Reader, root string, change Change) (bool, error) {
+	existing, err := os.ReadFile(change.Path)
+	if err != nil {
+		return false, err
+	}
+
+	path := relativePath(root, change.Path)
+	fmt.Printf("\n--- %s (existing)\n+++ %s (scaffold)\n", path, path)
+	fmt.Print(Diff(string(existing), change.Content))
+	for {
+		fmt.Printf("Overwrite %s? [y/N/q] ", path)
+		answer, err := prompt.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return false, err
+		}
+		switch strings.ToLower(strings.TrimSpace(answer)) {
+		case "y", "yes":
+			return true, nil
+		case "", "n", "no":
+			return false, nil
+		case "q", "quit":
+			return false, fmt.Errorf("scaffolding stopped; files answered so far were written")
+		}
+		if err == io.EOF {
+			return false, nil
+		}
+	}
+}
+
+// relativePath shows a scaffolded path relative to the project root when possible
+func relativePath(root, path string) string {
+	if rel, err := filepath.Rel(root, path); err == nil {
+		return rel
+	}
+	return path
+}
+
+// diffContext is the number of unchanged lines shown around each change
+const diffContext = 3
+
+// Diff returns a line diff of two texts: removed lines prefixed with "-", added lines with
+// "+" and up to diffContext unchanged lines around them prefixed with " ", separated by
+// "@@" where unchanged lines were left out; equal texts have an empty diff. Scaffolded files
+// are small, so a plain longest common subsequence table is good enough.
+func Diff(a, b string) string {
+	x, y := splitLines(a), splitLines(b)
+
+	// lcs[i][j] is the length of the longest common subsequence of x[i:] and y[j:]
+	lcs := make([][]int, len(x)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(y)+1)
+	}
+	for i := len(x) - 1; i >= 0; i-- {
+		for j := len(y) - 1; j >= 0; j-- {
+			switch {
+			case x[i] == y[j]:
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			case lcs[i+1][j] >= lcs[i][j+1]:
+				lcs[i][j] = lcs[i+1][j]
+			default:
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var lines []string
+	i, j := 0, 0
+	for i < len(x) || j < len(y) {
+		switch {
+		case i < len(x) && j < len(y) && x[i] == y[j]:
+			lines = append(lines, " "+x[i])
+			i++
+			j++
+		case i < len(x) && (j == len(y) || lcs[i+1][j] >= lcs[i][j+1]):
+			// Removals come before additions, as in unified diffs
+			lines = append(lines, "-"+x[i])
+			i++
+		default:
+			lines = append(lines, "+"+y[j])
+			j++
+		}
+	}
+
+	// Keep the changed lines and their context
+	keep := make([]bool, len(lines))
+	for n, line := range lines {
+		if line[0] == ' ' {
+			continue
+		}
+		for k := n - diffContext; k <= n+diffContext; k++ {
+			if k >= 0 && k < len(lines) {
+				keep[k] = true
+			}
+		}
+	}
+
+	var out strings.Builder
+	skipped := false
+	for n, line := range lines {
+		if !keep[n] {
+			skipped = true
+			continue
+		}
+		if skipped {
+			out.WriteString("@@\n")
+		}
+		skipped = false
+		out.WriteString(line + "\n")
+	}
+	if skipped && out.Len() > 0 {
+		out.WriteString("@@\n")
+	}
+	return out.String()
+}
+
+// splitLines splits text into lines without a trailing empty line for the final newline
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
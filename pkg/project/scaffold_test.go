@@ -0,0 +1,209 @@
+package project
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	numbered := func(from, to int, changed map[int]string) string {
+		var lines []string
+		for n := from; n <= to; n++ {
+			if line, ok := changed[n]; ok {
+				lines = append(lines, line)
+				continue
+			}
+			lines = append(lines, "line "+string(rune('a'+n-1)))
+		}
+		return strings.Join(lines, "\n") + "\n"
+	}
+	tests := []struct {
+		name string
+		a, b string
+		want string
+	}{
+		{"both empty", "", "", ""},
+		{"equal", "a\nb\n", "a\nb\n", ""},
+		{"missing final newline is not a change", "a\nb", "a\nb\n", ""},
+		{"from empty", "", "a\nb\n", "+a\n+b\n"},
+		{"to empty", "a\nb\n", "", "-a\n-b\n"},
+		{"additions only", "a\nd\n", "a\nb\nc\nd\n", " a\n+b\n+c\n d\n"},
+		{"deletions only", "a\nb\nc\nd\n", "a\nd\n", " a\n-b\n-c\n d\n"},
+		{"replacement", "a\nb\nc\n", "a\nB\nc\n", " a\n-b\n+B\n c\n"},
+		{
+			name: "context around one change",
+			a:    numbered(1, 10, nil),
+			b:    numbered(1, 10, map[int]string{5: "changed"}),
+			want: "@@\n line b\n line c\n line d\n-line e\n+changed\n line f\n line g\n line h\n@@\n",
+		},
+		{
+			name: "separate hunks",
+			a:    numbered(1, 12, nil),
+			b:    numbered(1, 12, map[int]string{1: "first", 12: "last"}),
+			want: "-line a\n+first\n line b\n line c\n line d\n@@\n line i\n line j\n line k\n-line l\n+last\n",
+		},
+		{
+			name: "overlapping context is one hunk",
+			a:    numbered(1, 8, nil),
+			b:    numbered(1, 8, map[int]string{2: "two", 7: "seven"}),
+			want: " line a\n-line b\n+two\n line c\n line d\n line e\n line f\n-line g\n+seven\n line h\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Diff(tt.a, tt.b); got != tt.want {
+				t.Errorf("Diff() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPlan(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, map[string]string{
+		filepath.Join(root, "same.txt"):    "same\n",
+		filepath.Join(root, "changed.txt"): "old\n",
+	})
+
+	got := Plan(map[string]string{
+		filepath.Join(root, "same.txt"):       "same\n",
+		filepath.Join(root, "changed.txt"):    "new\n",
+		filepath.Join(root, "src", "new.txt"): "new\n",
+	})
+	want := []Change{
+		{Path: filepath.Join(root, "changed.txt"), Action: ActionChange, Content: "new\n"},
+		{Path: filepath.Join(root, "same.txt"), Action: ActionUnchanged, Content: "same\n"},
+		{Path: filepath.Join(root, "src", "new.txt"), Action: ActionCreate, Content: "new\n"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Plan() = %+v, want %+v", got, want)
+	}
+}
+
+func TestScaffold(t *testing.T) {
+	existing := map[string]string{
+		"README.md":   "# my notes\n",
+		"src/main.py": "print('hi')\n",
+	}
+	scaffold := map[string]string{
+		"README.md":           "# app\n",
+		"src/main.py":         "print('hi')\n",
+		"src/app/__init__.py": "",
+		"pyproject.toml":      "[project]\n",
+	}
+	tests := []struct {
+		name    string
+		mode    Mode
+		existed bool
+		wantErr string
+		want    map[string]string
+	}{
+		{
+			name: "new directory",
+			mode: ModeAbort,
+			want: scaffold,
+		},
+		{
+			name:    "abort",
+			mode:    ModeAbort,
+			existed: true,
+			wantErr: "refusing to scaffold",
+			want:    existing,
+		},
+		{
+			name:    "merge",
+			mode:    ModeMerge,
+			existed: true,
+			want: map[string]string{
+				"README.md":           "# my notes\n",
+				"src/main.py":         "print('hi')\n",
+				"src/app/__init__.py": "",
+				"pyproject.toml":      "[project]\n",
+			},
+		},
+		{
+			name:    "force",
+			mode:    ModeForce,
+			existed: true,
+			want:    scaffold,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := filepath.Join(t.TempDir(), "app")
+			if tt.existed {
+				writeFiles(t, under(root, existing))
+			}
+
+			err := Scaffold(root, []string{filepath.Join(root, "tests")}, under(root, scaffold), tt.mode)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Scaffold() error = %v, want it to contain %q", err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := readTree(t, root); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("files after Scaffold() = %q, want %q", got, tt.want)
+			}
+			if _, err := os.Stat(filepath.Join(root, "tests")); (err == nil) != (tt.wantErr == "") {
+				t.Errorf("tests directory created = %v, want %v", err == nil, tt.wantErr == "")
+			}
+		})
+	}
+}
+
+func TestScaffoldIntoFile(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "app")
+	writeFiles(t, map[string]string{root: "not a directory"})
+	if err := Scaffold(root, nil, under(root, map[string]string{"README.md": ""}), ModeForce); err == nil {
+		t.Error("Scaffold() into a file succeeded")
+	}
+}
+
+// under joins slash-separated relative paths to root
+func under(root string, files map[string]string) map[string]string {
+	joined := make(map[string]string)
+	for path, content := range files {
+		joined[filepath.Join(root, filepath.FromSlash(path))] = content
+	}
+	return joined
+}
+
+func writeFiles(t *testing.T, files map[string]string) {
+	t.Helper()
+	for path, content := range files {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// readTree returns the files under root keyed by slash-separated relative path
+func readTree(t *testing.T, root string) map[string]string {
+	t.Helper()
+	files := make(map[string]string)
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(root, path)
+		files[filepath.ToSlash(rel)] = string(content)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return files
+}
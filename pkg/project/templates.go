@@ -0,0 +1,408 @@
+package project
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"sort"
+	"strings"
+	"text/template"
+
+	"devstation-cli/pkg/config"
+)
+
+// ManifestFile declares a user-defined template; the .tmpl files next to it are its scaffold
+const ManifestFile = "template.json"
+
+// Kinds of project a template scaffolds
+const (
+	KindPython = "python"
+	KindC      = "c"
+)
+
+// gitSourcePrefix marks a template source as a git repository to clone, e.g. git+/srv/templates.git
+const gitSourcePrefix = "git+"
+
+// Variable is a value a template asks for when a project is created, available to its
+// files as {{.Vars.<name>}}
+type Variable struct {
+	Name     string   `json:"name"`
+	Prompt   string   `json:"prompt,omitempty"`   // Question asked for the value; defaults to the name
+	Default  string   `json:"default,omitempty"`  // Used when no value is given
+	Choices  []string `json:"choices,omitempty"`  // Allowed values, if restricted
+	Required bool     `json:"required,omitempty"` // An empty value is an error
+}
+
+// Manifest is the template.json of a user-defined template
+type Manifest struct {
+	Name        string     `json:"name"`
+	Description string     `json:"description,omitempty"`
+	Type        string     `json:"type"` // KindPython or KindC
+	Variables   []Variable `json:"variables,omitempty"`
+	// Python templates only, like the built-in starters; scripts may use the {{command}}
+	// and {{package}} placeholders for the distribution and import names
+	Dependencies    []string          `json:"dependencies,omitempty"`
+	DevDependencies []string          `json:"dev_dependencies,omitempty"`
+	Scripts         map[string]string `json:"scripts,omitempty"`
+}
+
+// Template is a user-defined template found in a template source. Its files are rendered on
+// top of the built-in scaffold of its type and replace built-in files with the same path.
+type Template struct {
+	Manifest
+	Dir    string // Directory holding the manifest and the template files
+	Source string // Template source the template was found in
+}
+
+var (
+	templateName = regexp.MustCompile(`^[A-Za-z0-9]([A-Za-z0-9._-]*[A-Za-z0-9])?$`)
+	variableName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+)
+
+// Problems lists what is wrong with a manifest; an empty list means it is valid
+func (m Manifest) Problems() []string {
+	var problems []string
+	if !templateName.MatchString(m.Name) {
+		problems = append(problems, fmt.Sprintf("invalid name %q: use letters, digits, '.', '_' and '-'", m.Name))
+	}
+	if m.Type != KindPython && m.Type != KindC {
+		problems = append(problems, fmt.Sprintf("invalid type %q; expected %s or %s", m.Type, KindPython, KindC))
+	}
+	if m.Type == KindC && (len(m.Dependencies) > 0 || len(m.DevDependencies) > 0 || len(m.Scripts) > 0) {
+		problems = append(problems, "dependencies, dev_dependencies and scripts only apply to python templates")
+	}
+
+	seen := make(map[string]bool)
+	for _, v := range m.Variables {
+		if !variableName.MatchString(v.Name) {
+			problems = append(problems, fmt.Sprintf("invalid variable name %q: use letters, digits and '_', not starting with a digit", v.Name))
+		}
+		if seen[v.Name] {
+			problems = append(problems, fmt.Sprintf("variable %q is declared twice", v.Name))
+		}
+		seen[v.Name] = true
+		if v.Default != "" && len(v.Choices) > 0 && !contains(v.Choices, v.Default) {
+			problems = append(problems, fmt.Sprintf("default %q of variable %q is not one of its choices", v.Default, v.Name))
+		}
+	}
+	return problems
+}
+
+// LoadTemplate reads and validates the manifest of the template in dir
+func LoadTemplate(dir, source string) (Template, error) {
+	content, err := os.ReadFile(filepath.Join(dir, ManifestFile))
+	if err != nil {
+		return Template{}, err
+	}
+	var manifest Manifest
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&manifest); err != nil {
+		return Template{}, fmt.Errorf("invalid %s: %v", filepath.Join(dir, ManifestFile), err)
+	}
+	if problems := manifest.Problems(); len(problems) > 0 {
+		return Template{}, fmt.Errorf("invalid %s: %s", filepath.Join(dir, ManifestFile), strings.Join(problems, "; "))
+	}
+	return Template{Manifest: manifest, Dir: dir, Source: source}, nil
+}
+
+// Render renders the template's files for a project
+func (t Template) Render(data Data, extra template.FuncMap) (map[string]string, error) {
+	return Render(os.DirFS(t.Dir), ".", data, extra)
+}
+
+// UnusedVariables lists the variables no file of the template refers to as {{.Vars.<name>}}
+func (t Template) UnusedVariables() ([]string, error) {
+	var content strings.Builder
+	err := filepath.WalkDir(t.Dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() && entry.Name() == ".git" {
+			return filepath.SkipDir
+		}
+		if entry.IsDir() {
+			return nil
+		}
+		if strings.HasSuffix(path, TemplateExt) {
+			text, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			content.Write(text)
+		}
+		// Paths are templates too
+		content.WriteString(path)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var unused []string
+	for _, v := range t.Variables {
+		if !strings.Contains(content.String(), ".Vars."+v.Name) {
+			unused = append(unused, v.Name)
+		}
+	}
+	return unused, nil
+}
+
+// TemplateSources returns the configured template sources, in order of precedence
+func TemplateSources() []string {
+	return config.Current().Templates.Sources
+}
+
+// FindTemplates lists the templates of the sources. A source is a template directory itself
+// or a directory of template directories; git+PATH sources are cloned first. Sources and
+// templates that cannot be read are returned as errors alongside the others.
+func FindTemplates(sources []string) ([]Template, []error) {
+	var templates []Template
+	var errs []error
+	for _, source := range sources {
+		dir, err := resolveSource(source)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		if fileExists(filepath.Join(dir, ManifestFile)) {
+			t, err := LoadTemplate(dir, source)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			templates = append(templates, t)
+			continue
+		}
+
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("template source %s: %v", source, err))
+			continue
+		}
+		for _, entry := range entries {
+			templateDir := filepath.Join(dir, entry.Name())
+			if !entry.IsDir() || !fileExists(filepath.Join(templateDir, ManifestFile)) {
+				continue
+			}
+			t, err := LoadTemplate(templateDir, source)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			templates = append(templates, t)
+		}
+	}
+	return templates, errs
+}
+
+// FindTemplate returns the template of the given type and name from the first source that
+// has it. An empty name selects the only template of that type in the sources.
+func FindTemplate(kind, name string, sources []string) (Template, error) {
+	templates, errs := FindTemplates(sources)
+	for _, err := range errs {
+		fmt.Printf("Warning: %v\n", err)
+	}
+
+	var matches []Template
+	for _, t := range templates {
+		if t.Type == kind && (name == "" || t.Name == name) {
+			if name != "" {
+				return t, nil
+			}
+			matches = append(matches, t)
+		}
+	}
+	switch {
+	case name != "":
+		return Template{}, fmt.Errorf("unknown %s template %q; see 'devstation templates list'", kind, name)
+	case len(matches) == 0:
+		return Template{}, fmt.Errorf("no %s template in %s", kind, strings.Join(sources, ", "))
+	case len(matches) > 1:
+		var names []string
+		for _, t := range matches {
+			names = append(names, t.Name)
+		}
+		return Template{}, fmt.Errorf("%s has several %s templates; choose one with --template: %s", strings.Join(sources, ", "), kind, strings.Join(names, ", "))
+	}
+	return matches[0], nil
+}
+
+// resolveSource returns the local directory of a template source, cloning or updating git
+// sources in the user cache directory
+func resolveSource(source string) (string, error) {
+	if !strings.HasPrefix(source, gitSourcePrefix) {
+		info, err := os.Stat(source)
+		if err != nil {
+			return "", fmt.Errorf("template source %s: %v", source, err)
+		}
+		if !info.IsDir() {
+			return "", fmt.Errorf("template source %s is not a directory", source)
+		}
+		return source, nil
+	}
+
+	repo := strings.TrimPrefix(source, gitSourcePrefix)
+	// Only repositories on a local path are cloned; a one-letter scheme is a Windows drive
+	if u, err := url.Parse(repo); err == nil && u.Scheme == "file" {
+		repo = u.Path
+		// file:///C:/templates.git has the path "/C:/templates.git"
+		if runtime.GOOS == "windows" && len(repo) > 2 && repo[0] == '/' && repo[2] == ':' {
+			repo = repo[1:]
+		}
+		repo = filepath.FromSlash(repo)
+	} else if err == nil && len(u.Scheme) > 1 {
+		return "", fmt.Errorf("template source %s: only git repositories on a local path are supported", source)
+	}
+	if _, err := os.Stat(repo); err != nil {
+		return "", fmt.Errorf("template source %s: %v", source, err)
+	}
+
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate user cache directory: %v", err)
+	}
+	sum := sha256.Sum256([]byte(source))
+	dir := filepath.Join(cacheDir, "devstation", "templates", hex.EncodeToString(sum[:8]))
+
+	if !fileExists(dir) {
+		if output, err := exec.Command("git", "clone", "--quiet", repo, dir).CombinedOutput(); err != nil {
+			return "", fmt.Errorf("failed to clone template source %s: %v: %s", source, err, strings.TrimSpace(string(output)))
+		}
+		return dir, nil
+	}
+	// Keep using the last clone when the repository cannot be updated
+	if output, err := exec.Command("git", "-C", dir, "pull", "--quiet", "--ff-only").CombinedOutput(); err != nil {
+		fmt.Printf("Warning: failed to update template source %s, using the cached copy: %s\n", source, strings.TrimSpace(string(output)))
+	}
+	return dir, nil
+}
+
+// ResolveVars fills in a template's variables from the given values. Variables without a
+// value are asked for when prompt is set and take their default otherwise.
+func ResolveVars(variables []Variable, given map[string]string, prompt bool) (map[string]string, error) {
+	declared := make(map[string]bool)
+	for _, v := range variables {
+		declared[v.Name] = true
+	}
+	var unknown []string
+	for name := range given {
+		if !declared[name] {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return nil, fmt.Errorf("the template has no variable %s", strings.Join(unknown, ", "))
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+	vars := make(map[string]string)
+	for _, v := range variables {
+		value, ok := given[v.Name]
+		if !ok && prompt {
+			answer, err := askVariable(reader, v)
+			if err != nil {
+				return nil, err
+			}
+			value, ok = answer, true
+		}
+		if !ok || value == "" {
+			value = v.Default
+		}
+		if err := checkVariable(v, value); err != nil {
+			return nil, err
+		}
+		vars[v.Name] = value
+	}
+	return vars, nil
+}
+
+// askVariable prompts for a variable until the answer is acceptable; an empty answer takes
+// the default
+func askVariable(reader *bufio.Reader, v Variable) (string, error) {
+	question := v.Prompt
+	if question == "" {
+		question = v.Name
+	}
+	if len(v.Choices) > 0 {
+		question += " (" + strings.Join(v.Choices, "/") + ")"
+	}
+	if v.Default != "" {
+		question += " [" + v.Default + "]"
+	}
+
+	for {
+		fmt.Printf("%s: ", question)
+		line, err := reader.ReadString('\n')
+		answer := strings.TrimSpace(line)
+		if answer == "" {
+			answer = v.Default
+		}
+		if err != nil {
+			// At the end of input the answer is checked again by the caller
+			fmt.Println()
+			return answer, nil
+		}
+		checkErr := checkVariable(v, answer)
+		if checkErr == nil {
+			return answer, nil
+		}
+		fmt.Printf("  %v\n", checkErr)
+	}
+}
+
+// checkVariable reports whether a value is acceptable for a variable
+func checkVariable(v Variable, value string) error {
+	if value == "" && v.Required {
+		return fmt.Errorf("variable %s is required; pass --var %s=VALUE", v.Name, v.Name)
+	}
+	if value != "" && len(v.Choices) > 0 && !contains(v.Choices, value) {
+		return fmt.Errorf("invalid value %q for variable %s; expected one of %s", value, v.Name, strings.Join(v.Choices, ", "))
+	}
+	return nil
+}
+
+// ExampleVars returns values for a template's variables to render an example project with:
+// the default, the first choice or the variable's name
+func ExampleVars(variables []Variable) map[string]string {
+	vars := make(map[string]string)
+	for _, v := range variables {
+		switch {
+		case v.Default != "":
+			vars[v.Name] = v.Default
+		case len(v.Choices) > 0:
+			vars[v.Name] = v.Choices[0]
+		default:
+			vars[v.Name] = v.Name
+		}
+	}
+	return vars
+}
+
+// contains reports whether items contains item
+func contains(items []string, item string) bool {
+	for _, i := range items {
+		if i == item {
+			return true
+		}
+	}
+	return false
+}
+
+// fileExists reports whether path exists
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
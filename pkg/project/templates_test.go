@@ -0,0 +1,479 @@
+package project
+
+import (
+	"bufio"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestManifestProblems(t *testing.T) {
+	tests := []struct {
+		name     string
+		manifest Manifest
+		want     []string
+	}{
+		{"valid python", Manifest{Name: "service", Type: KindPython, Dependencies: []string{"flask"}, Scripts: map[string]string{"{{command}}": "{{package}}.cli:main"}}, nil},
+		{"valid c", Manifest{Name: "fw.2", Type: KindC, Variables: []Variable{{Name: "board", Choices: []string{"a", "b"}, Default: "b"}}}, nil},
+		{"bad name", Manifest{Name: "my service", Type: KindPython}, []string{"invalid name"}},
+		{"empty name", Manifest{Type: KindPython}, []string{"invalid name"}},
+		{"bad type", Manifest{Name: "x", Type: "rust"}, []string{"invalid type"}},
+		{"c with dependencies", Manifest{Name: "x", Type: KindC, Dependencies: []string{"zlib"}}, []string{"only apply to python"}},
+		{"c with scripts", Manifest{Name: "x", Type: KindC, Scripts: map[string]string{"a": "b"}}, []string{"only apply to python"}},
+		{"bad variable name", Manifest{Name: "x", Type: KindC, Variables: []Variable{{Name: "1board"}}}, []string{"invalid variable name"}},
+		{"duplicate variable", Manifest{Name: "x", Type: KindC, Variables: []Variable{{Name: "a"}, {Name: "a"}}}, []string{"declared twice"}},
+		{"default not a choice", Manifest{Name: "x", Type: KindC, Variables: []Variable{{Name: "a", Choices: []string{"b"}, Default: "c"}}}, []string{"not one of its choices"}},
+		{"several problems", Manifest{Name: "-", Type: ""}, []string{"invalid name", "invalid type"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.manifest.Problems()
+			if len(got) != len(tt.want) {
+				t.Fatalf("Problems() = %q, want %d problems matching %q", got, len(tt.want), tt.want)
+			}
+			for i, want := range tt.want {
+				if !strings.Contains(got[i], want) {
+					t.Errorf("Problems()[%d] = %q, want it to contain %q", i, got[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestLoadTemplate(t *testing.T) {
+	tests := []struct {
+		name     string
+		manifest string
+		want     Manifest
+		wantErr  string
+	}{
+		{
+			name:     "valid",
+			manifest: `{"name": "service", "description": "Team service", "type": "python", "variables": [{"name": "team", "required": true}], "dependencies": ["flask"]}`,
+			want:     Manifest{Name: "service", Description: "Team service", Type: KindPython, Variables: []Variable{{Name: "team", Required: true}}, Dependencies: []string{"flask"}},
+		},
+		{name: "invalid json", manifest: `{"name": `, wantErr: "invalid"},
+		{name: "unknown field", manifest: `{"name": "x", "type": "c", "flavour": "mild"}`, wantErr: "unknown field"},
+		{name: "invalid manifest", manifest: `{"name": "x", "type": "go"}`, wantErr: "invalid type"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := writeTemplate(t, t.TempDir(), tt.manifest, nil)
+			got, err := LoadTemplate(dir, "source")
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("LoadTemplate() error = %v, want it to contain %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, Template{Manifest: tt.want, Dir: dir, Source: "source"}) {
+				t.Errorf("LoadTemplate() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := LoadTemplate(t.TempDir(), "source"); err == nil {
+		t.Error("LoadTemplate() without a manifest succeeded")
+	}
+}
+
+func TestTemplateRender(t *testing.T) {
+	dir := writeTemplate(t, t.TempDir(), `{"name": "fw", "type": "c", "variables": [{"name": "board"}, {"name": "unused"}]}`, map[string]string{
+		"README.md.tmpl":                "# {{.Name}} on {{.Vars.board}}\n",
+		"boards/{{.Vars.board}}.h.tmpl": "#define BOARD \"{{.Vars.board}}\"\n",
+		"LICENSE":                       "not a template\n",
+	})
+	tmpl, err := LoadTemplate(dir, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	name, err := ParseName("fw")
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts := Options{Vars: map[string]string{"board": "stm32"}}
+	files, err := tmpl.Render(NewData(name, opts, nil), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		"README.md":                        "# fw on stm32\n",
+		filepath.Join("boards", "stm32.h"): "#define BOARD \"stm32\"\n",
+	}
+	for path, content := range want {
+		if got := files[path]; got != content {
+			t.Errorf("%s = %q, want %q", path, got, content)
+		}
+	}
+	if len(files) != len(want) {
+		t.Errorf("Render() = %d files, want only the .tmpl files %v", len(files), want)
+	}
+
+	unused, err := tmpl.UnusedVariables()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(unused, []string{"unused"}) {
+		t.Errorf("UnusedVariables() = %q, want [unused]", unused)
+	}
+}
+
+func TestFindTemplates(t *testing.T) {
+	team := t.TempDir()
+	writeTemplate(t, filepath.Join(team, "service"), `{"name": "service", "type": "python"}`, nil)
+	writeTemplate(t, filepath.Join(team, "firmware"), `{"name": "firmware", "type": "c"}`, nil)
+	writeTemplate(t, filepath.Join(team, "broken"), `{"name": "broken", "type": "go"}`, nil)
+	if err := os.MkdirAll(filepath.Join(team, "notes"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	single := writeTemplate(t, t.TempDir(), `{"name": "service", "type": "python", "description": "personal"}`, nil)
+	other := t.TempDir()
+	writeTemplate(t, filepath.Join(other, "api"), `{"name": "api", "type": "python"}`, nil)
+	writeTemplate(t, filepath.Join(other, "worker"), `{"name": "worker", "type": "python"}`, nil)
+	missing := filepath.Join(t.TempDir(), "missing")
+
+	templates, errs := FindTemplates([]string{single, team, missing})
+	var found []string
+	for _, tmpl := range templates {
+		found = append(found, tmpl.Name+"@"+tmpl.Source)
+	}
+	wantFound := []string{"service@" + single, "firmware@" + team, "service@" + team}
+	if !reflect.DeepEqual(found, wantFound) {
+		t.Errorf("FindTemplates() found %q, want %q", found, wantFound)
+	}
+	if len(errs) != 2 || !strings.Contains(errs[0].Error(), "invalid type") || !strings.Contains(errs[1].Error(), missing) {
+		t.Errorf("FindTemplates() errors = %v, want the broken template and the missing source", errs)
+	}
+
+	tests := []struct {
+		name     string
+		kind     string
+		template string
+		sources  []string
+		wantDir  string
+		wantErr  string
+	}{
+		{"by name, first source wins", KindPython, "service", []string{single, team}, single, ""},
+		{"by name in a later source", KindC, "firmware", []string{single, team}, filepath.Join(team, "firmware"), ""},
+		{"only template of the type", KindC, "", []string{single, team}, filepath.Join(team, "firmware"), ""},
+		{"several templates of the type", KindPython, "", []string{other}, "", "choose one with --template: api, worker"},
+		{"unknown name", KindPython, "nope", []string{team}, "", `unknown python template "nope"`},
+		{"type mismatch", KindC, "service", []string{team}, "", `unknown c template "service"`},
+		{"no template of the type", KindC, "", []string{other}, "", "no c template in " + other},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FindTemplate(tt.kind, tt.template, tt.sources)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("FindTemplate() error = %v, want it to contain %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Dir != tt.wantDir {
+				t.Errorf("FindTemplate() = %s, want %s", got.Dir, tt.wantDir)
+			}
+		})
+	}
+}
+
+func TestResolveSourceDirectory(t *testing.T) {
+	dir := t.TempDir()
+	if got, err := resolveSource(dir); err != nil || got != dir {
+		t.Errorf("resolveSource(%q) = %q, %v; want the directory itself", dir, got, err)
+	}
+	file := filepath.Join(dir, "file")
+	if err := os.WriteFile(file, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := resolveSource(file); err == nil || !strings.Contains(err.Error(), "not a directory") {
+		t.Errorf("resolveSource(file) error = %v, want a not a directory error", err)
+	}
+	if _, err := resolveSource(filepath.Join(dir, "missing")); err == nil {
+		t.Error("resolveSource() of a missing directory succeeded")
+	}
+}
+
+func TestResolveSourceGit(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	cache := t.TempDir()
+	t.Setenv("XDG_CACHE_HOME", cache)
+	t.Setenv("HOME", cache)
+	t.Setenv("LocalAppData", cache)
+	userCache, err := os.UserCacheDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bare := filepath.Join(t.TempDir(), "templates.git")
+	work := filepath.Join(t.TempDir(), "work")
+	git(t, "", "init", "--quiet", "--bare", bare)
+	git(t, "", "clone", "--quiet", bare, work)
+	writeTemplate(t, filepath.Join(work, "service"), `{"name": "service", "type": "python"}`, nil)
+	git(t, work, "add", "-A")
+	git(t, work, "commit", "--quiet", "-m", "Add service")
+	git(t, work, "push", "--quiet", "origin", "HEAD")
+
+	source := gitSourcePrefix + bare
+	dir, err := resolveSource(source)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(dir, filepath.Join(userCache, "devstation", "templates")+string(filepath.Separator)) {
+		t.Errorf("resolveSource() cloned into %s, want a directory in the user cache", dir)
+	}
+	if !fileExists(filepath.Join(dir, "service", ManifestFile)) {
+		t.Fatalf("clone %s has no service template", dir)
+	}
+
+	// A second resolve pulls new commits into the same clone
+	writeTemplate(t, filepath.Join(work, "worker"), `{"name": "worker", "type": "python"}`, nil)
+	git(t, work, "add", "-A")
+	git(t, work, "commit", "--quiet", "-m", "Add worker")
+	git(t, work, "push", "--quiet", "origin", "HEAD")
+	again, err := resolveSource(source)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again != dir || !fileExists(filepath.Join(dir, "worker", ManifestFile)) {
+		t.Errorf("resolveSource() again = %s without the new worker template, want an updated %s", again, dir)
+	}
+
+	// file:// URLs name the same repository but are cached separately
+	fileURL := "file://" + filepath.ToSlash(bare)
+	if runtime.GOOS == "windows" {
+		fileURL = "file:///" + filepath.ToSlash(bare)
+	}
+	fromURL, err := resolveSource(gitSourcePrefix + fileURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fromURL == dir || !fileExists(filepath.Join(fromURL, "worker", ManifestFile)) {
+		t.Errorf("resolveSource(file URL) = %s, want a separate clone with both templates", fromURL)
+	}
+
+	templates, errs := FindTemplates([]string{source})
+	if len(errs) > 0 || len(templates) != 2 || templates[0].Source != source {
+		t.Errorf("FindTemplates(git source) = %+v, %v; want service and worker from %s", templates, errs, source)
+	}
+
+	for _, bad := range []string{"git+https://example.com/templates.git", "git+ssh://git@example.com/templates.git", "git+" + filepath.Join(t.TempDir(), "missing.git")} {
+		if _, err := resolveSource(bad); err == nil {
+			t.Errorf("resolveSource(%q) succeeded", bad)
+		}
+	}
+}
+
+func TestResolveVars(t *testing.T) {
+	variables := []Variable{
+		{Name: "team", Required: true},
+		{Name: "db", Choices: []string{"postgres", "sqlite"}, Default: "sqlite"},
+		{Name: "port", Default: "8080"},
+		{Name: "owner"},
+	}
+	tests := []struct {
+		name    string
+		given   map[string]string
+		want    map[string]string
+		wantErr string
+	}{
+		{
+			name:  "given values and defaults",
+			given: map[string]string{"team": "core", "db": "postgres"},
+			want:  map[string]string{"team": "core", "db": "postgres", "port": "8080", "owner": ""},
+		},
+		{
+			name:  "empty value takes the default",
+			given: map[string]string{"team": "core", "port": ""},
+			want:  map[string]string{"team": "core", "db": "sqlite", "port": "8080", "owner": ""},
+		},
+		{name: "missing required", given: map[string]string{"db": "sqlite"}, wantErr: "variable team is required; pass --var team=VALUE"},
+		{name: "invalid choice", given: map[string]string{"team": "core", "db": "mysql"}, wantErr: `invalid value "mysql" for variable db; expected one of postgres, sqlite`},
+		{name: "unknown variables", given: map[string]string{"team": "core", "zone": "eu", "colour": "red"}, wantErr: "the template has no variable colour, zone"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ResolveVars(variables, tt.given, false)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ResolveVars() error = %v, want it to contain %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ResolveVars() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveVarsPrompt(t *testing.T) {
+	variables := []Variable{
+		{Name: "team", Required: true},
+		{Name: "db", Choices: []string{"postgres", "sqlite"}, Default: "sqlite"},
+		{Name: "port", Default: "8080"},
+	}
+	tests := []struct {
+		name    string
+		given   map[string]string
+		input   string
+		want    map[string]string
+		wantErr string
+	}{
+		{
+			name:  "answers and defaults",
+			input: "core\n\n9000\n",
+			want:  map[string]string{"team": "core", "db": "sqlite", "port": "9000"},
+		},
+		{
+			name:  "given values are not asked for",
+			given: map[string]string{"team": "core"},
+			input: "postgres\n\n",
+			want:  map[string]string{"team": "core", "db": "postgres", "port": "8080"},
+		},
+		{
+			name:  "invalid answers are asked again",
+			input: "\ncore\nmysql\npostgres\n\n",
+			want:  map[string]string{"team": "core", "db": "postgres", "port": "8080"},
+		},
+		{
+			name:    "end of input without a required value",
+			input:   "",
+			wantErr: "variable team is required",
+		},
+		{
+			name:  "end of input takes the defaults",
+			input: "core\n",
+			want:  map[string]string{"team": "core", "db": "sqlite", "port": "8080"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			withStdin(t, tt.input)
+			got, err := ResolveVars(variables, tt.given, true)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ResolveVars() error = %v, want it to contain %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ResolveVars() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAskVariable(t *testing.T) {
+	v := Variable{Name: "db", Prompt: "Database", Choices: []string{"postgres", "sqlite"}, Default: "sqlite"}
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"postgres\n", "postgres"},
+		{"  postgres  \n", "postgres"},
+		{"\n", "sqlite"},
+		{"mysql\nsqlite\n", "sqlite"},
+		{"postgres", "postgres"},
+		{"", "sqlite"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := askVariable(bufio.NewReader(strings.NewReader(tt.input)), v)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("askVariable(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExampleVars(t *testing.T) {
+	got := ExampleVars([]Variable{
+		{Name: "db", Choices: []string{"postgres", "sqlite"}, Default: "sqlite"},
+		{Name: "region", Choices: []string{"eu", "us"}},
+		{Name: "port", Default: "8080"},
+		{Name: "team", Required: true},
+	})
+	want := map[string]string{"db": "sqlite", "region": "eu", "port": "8080", "team": "team"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ExampleVars() = %v, want %v", got, want)
+	}
+	if got := ExampleVars(nil); len(got) != 0 {
+		t.Errorf("ExampleVars(nil) = %v, want no values", got)
+	}
+}
+
+// writeTemplate writes a manifest and template files into dir and returns dir
+func writeTemplate(t *testing.T, dir, manifest string, files map[string]string) string {
+	t.Helper()
+	all := map[string]string{ManifestFile: manifest}
+	for path, content := range files {
+		all[path] = content
+	}
+	paths := make([]string, 0, len(all))
+	for path := range all {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		target := filepath.Join(dir, filepath.FromSlash(path))
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(target, []byte(all[path]), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+// git runs a git command in dir with a fixed identity
+func git(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", append([]string{"-c", "user.name=Test", "-c", "user.email=test@example.com", "-c", "init.defaultBranch=main"}, args...)...)
+	cmd.Dir = dir
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %s: %v: %s", strings.Join(args, " "), err, output)
+	}
+}
+
+// withStdin replaces os.Stdin with input for the rest of the test
+func withStdin(t *testing.T, input string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "stdin")
+	if err := os.WriteFile(path, []byte(input), 0644); err != nil {
+		t.Fatal(err)
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	saved := os.Stdin
+	os.Stdin = file
+	t.Cleanup(func() {
+		os.Stdin = saved
+		file.Close()
+	})
+}
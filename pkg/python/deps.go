@@ -0,0 +1,403 @@
+package python
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Per-manager commands that add or remove a dependency, updating the declaration, the
+// lock file and the environment in one step. Hatch has none, so its pyproject.toml is
+// edited directly.
+var (
+	managerAddCommands = map[string][]string{
+		"poetry": {"poetry", "add"},
+		"uv":     {"uv", "add"},
+		"pipenv": {"pipenv", "install"},
+	}
+	managerAddDevArgs = map[string][]string{
+		"poetry": {"--optional", "dev"},
+		"uv":     {"--optional", "dev"},
+		"pipenv": {"--dev"},
+	}
+	managerRemoveCommands = map[string][]string{
+		"poetry": {"poetry", "remove"},
+		"uv":     {"uv", "remove"},
+		"pipenv": {"pipenv", "uninstall"},
+	}
+)
+
+// AddDependency declares a dependency in the project's requirement files and pyproject.toml,
+// or through the project's manager, then installs it into the venv and refreshes the lock
+// files when the project has them
+func (p *PythonSetup) AddDependency(projectDir string, venv Venv, requirement string, dev bool) error {
+	name := requirementName(requirement)
+	if name == "" {
+		return fmt.Errorf("invalid requirement %q", requirement)
+	}
+
+	managerName := DetectProjectManager(projectDir)
+	if command, ok := managerAddCommands[managerName]; ok {
+		args := append([]string{}, command...)
+		if dev {
+			args = append(args, managerAddDevArgs[managerName]...)
+		}
+		return p.runProjectManager(ProjectManagers[managerName], projectDir, append(args, requirement))
+	}
+
+	files, err := declareDependency(projectDir, requirement, dev)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Added %s to %s\n", requirement, strings.Join(files, ", "))
+
+	if managerName == "hatch" {
+		fmt.Println("Hatch installs it into the environment on the next 'hatch run'")
+		return nil
+	}
+	return p.applyDependencyChange(projectDir, venv, func(venvSetup *PythonSetup) error {
+		return venvSetup.runPipInstall(requirement)
+	})
+}
+
+// RemoveDependency removes a dependency from the project's declarations, or through the
+// project's manager, then uninstalls it from the venv and refreshes the lock files
+func (p *PythonSetup) RemoveDependency(projectDir string, venv Venv, name string) error {
+	managerName := DetectProjectManager(projectDir)
+	if command, ok := managerRemoveCommands[managerName]; ok {
+		return p.runProjectManager(ProjectManagers[managerName], projectDir, append(append([]string{}, command...), name))
+	}
+
+	files, err := undeclareDependency(projectDir, name)
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("%s is not a dependency of the project", name)
+	}
+	fmt.Printf("Removed %s from %s\n", name, strings.Join(files, ", "))
+
+	if managerName == "hatch" {
+		return nil
+	}
+	return p.applyDependencyChange(projectDir, venv, func(venvSetup *PythonSetup) error {
+		cmd := exec.Command(venvSetup.Python(), "-m", "pip", "uninstall", "--yes", name)
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		return cmd.Run()
+	})
+}
+
+// applyDependencyChange brings the venv in line with changed declarations: locked projects
+// are re-locked and synced, others get the single change applied with pip
+func (p *PythonSetup) applyDependencyChange(projectDir string, venv Venv, apply func(*PythonSetup) error) error {
+	if !venv.Exists() {
+		fmt.Printf("No virtual environment at %s; run 'devstation venv create' to install the project\n", venv.Dir)
+		return nil
+	}
+
+	if hasLockFiles(projectDir) {
+		if err := p.Lock(projectDir, venv, false); err != nil {
+			return err
+		}
+		return p.Sync(projectDir, venv, true)
+	}
+	return apply(p.ForVenv(venv))
+}
+
+// hasLockFiles reports whether any requirements input file has been compiled by pip-compile
+func hasLockFiles(projectDir string) bool {
+	for _, input := range requirementInputs(projectDir) {
+		content, err := os.ReadFile(filepath.Join(projectDir, lockFile(input)))
+		if err == nil && strings.Contains(string(content), "pip-compile") {
+			return true
+		}
+	}
+	return false
+}
+
+// dependencyFiles returns the requirement files that declare runtime or dev dependencies:
+// the pip-compile inputs when present, else plain requirements files
+func dependencyFiles(projectDir string, dev bool) []string {
+	if len(requirementInputs(projectDir)) > 0 {
+		if dev {
+			return []string{DevRequirementsIn}
+		}
+		return []string{RequirementsIn}
+	}
+	if dev {
+		if fileExists(filepath.Join(projectDir, "requirements-dev.txt")) {
+			return []string{"requirements-dev.txt"}
+		}
+		return nil
+	}
+	if fileExists(filepath.Join(projectDir, "requirements.txt")) {
+		return []string{"requirements.txt"}
+	}
+	return nil
+}
+
+// declareDependency adds or replaces a requirement in the project's requirement files and
+// pyproject.toml, returning the files changed
+func declareDependency(projectDir, requirement string, dev bool) ([]string, error) {
+	var changed []string
+
+	for _, name := range dependencyFiles(projectDir, dev) {
+		path := filepath.Join(projectDir, name)
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return changed, err
+		}
+		updated := setRequirementLine(string(content), requirement)
+		if err := os.WriteFile(path, []byte(updated), 0644); err != nil {
+			return changed, fmt.Errorf("failed to update %s: %v", name, err)
+		}
+		changed = append(changed, name)
+	}
+
+	pyprojectPath := filepath.Join(projectDir, "pyproject.toml")
+	if content, err := os.ReadFile(pyprojectPath); err == nil && strings.Contains(string(content), "[project]") {
+		section, key := "project", "dependencies"
+		if dev {
+			section, key = "project.optional-dependencies", "dev"
+		}
+		updated := editTomlArray(string(content), section, key, func(items []string) []string {
+			return setRequirement(items, requirement)
+		})
+		if err := os.WriteFile(pyprojectPath, []byte(updated), 0644); err != nil {
+			return changed, fmt.Errorf("failed to update pyproject.toml: %v", err)
+		}
+		changed = append(changed, "pyproject.toml")
+	}
+
+	if len(changed) == 0 {
+		return nil, fmt.Errorf("no requirements file or pyproject.toml [project] table in %s to declare the dependency in", projectDir)
+	}
+	return changed, nil
+}
+
+// undeclareDependency removes a requirement from every requirement file and from the
+// pyproject.toml dependency lists, returning the files changed
+func undeclareDependency(projectDir, name string) ([]string, error) {
+	var changed []string
+
+	for _, file := range append(dependencyFiles(projectDir, false), dependencyFiles(projectDir, true)...) {
+		path := filepath.Join(projectDir, file)
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return changed, err
+		}
+		updated := removeRequirementLine(string(content), name)
+		if updated == string(content) {
+			continue
+		}
+		if err := os.WriteFile(path, []byte(updated), 0644); err != nil {
+			return changed, fmt.Errorf("failed to update %s: %v", file, err)
+		}
+		changed = append(changed, file)
+	}
+
+	pyprojectPath := filepath.Join(projectDir, "pyproject.toml")
+	if content, err := os.ReadFile(pyprojectPath); err == nil {
+		remove := func(items []string) []string { return removeRequirement(items, name) }
+		updated := editTomlArray(string(content), "project", "dependencies", remove)
+		updated = editTomlArray(updated, "project.optional-dependencies", "dev", remove)
+		if updated != string(content) {
+			if err := os.WriteFile(pyprojectPath, []byte(updated), 0644); err != nil {
+				return changed, fmt.Errorf("failed to update pyproject.toml: %v", err)
+			}
+			changed = append(changed, "pyproject.toml")
+		}
+	}
+	return changed, nil
+}
+
+// requirementName returns the normalized distribution name of a requirement line
+func requirementName(requirement string) string {
+	name, _ := splitRequirement(strings.TrimSpace(requirement))
+	if i := strings.Index(name, "@"); i >= 0 {
+		name = name[:i]
+	}
+	return NormalizePackageName(strings.TrimSpace(name))
+}
+
+// setRequirement replaces the requirement with the same name in items, or appends it
+func setRequirement(items []string, requirement string) []string {
+	name := requirementName(requirement)
+	for i, item := range items {
+		if requirementName(item) == name {
+			items[i] = requirement
+			return items
+		}
+	}
+	return append(items, requirement)
+}
+
+// removeRequirement drops the requirements with the given name from items
+func removeRequirement(items []string, name string) []string {
+	var kept []string
+	for _, item := range items {
+		if requirementName(item) != NormalizePackageName(name) {
+			kept = append(kept, item)
+		}
+	}
+	return kept
+}
+
+// isRequirementLine reports whether a requirements file line declares a package rather
+// than being blank, a comment or a pip option
+func isRequirementLine(line string) bool {
+	line = strings.TrimSpace(line)
+	return line != "" && !strings.HasPrefix(line, "#") && !strings.HasPrefix(line, "-")
+}
+
+// setRequirementLine replaces the line declaring the same package in a requirements file,
+// or appends the requirement
+func setRequirementLine(content, requirement string) string {
+	lines := strings.Split(strings.TrimRight(content, "\n"), "\n")
+	name := requirementName(requirement)
+	for i, line := range lines {
+		if isRequirementLine(line) && requirementName(line) == name {
+			lines[i] = requirement
+			return strings.Join(lines, "\n") + "\n"
+		}
+	}
+	return strings.Join(append(lines, requirement), "\n") + "\n"
+}
+
+// removeRequirementLine drops the lines declaring a package from a requirements file
+func removeRequirementLine(content, name string) string {
+	var kept []string
+	for _, line := range strings.Split(strings.TrimRight(content, "\n"), "\n") {
+		if isRequirementLine(line) && requirementName(line) == NormalizePackageName(name) {
+			continue
+		}
+		kept = append(kept, line)
+	}
+	return strings.Join(kept, "\n") + "\n"
+}
+
+var (
+	tomlHeader           = regexp.MustCompile(`^\s*\[([^\[\]]+)\]\s*(#.*)?$`)
+	tomlArrayTableHeader = regexp.MustCompile(`^\s*\[\[[^\[\]]+\]\]\s*(#.*)?$`)
+	tomlQuotedValue      = regexp.MustCompile(`"((?:[^"\\]|\\.)*)"|'([^']*)'`)
+	tomlLiteralEscaper   = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+)
+
+// editTomlArray rewrites the string array key in a TOML table, creating the key or the
+// table when it does not exist. Only the array is reformatted; the rest of the document
+// is left untouched.
+func editTomlArray(content, table, key string, edit func([]string) []string) string {
+	lines := strings.Split(content, "\n")
+	keyPattern := regexp.MustCompile(`^\s*"?` + regexp.QuoteMeta(key) + `"?\s*=\s*\[`)
+
+	headerLine := -1
+	for i, line := range lines {
+		if match := tomlHeader.FindStringSubmatch(line); match != nil && strings.TrimSpace(match[1]) == table {
+			headerLine = i
+			break
+		}
+	}
+
+	if headerLine < 0 {
+		items := edit(nil)
+		if len(items) == 0 {
+			return content
+		}
+		added := fmt.Sprintf("[%s]\n%s = %s\n", table, key, tomlList(items))
+		if strings.TrimSpace(content) == "" {
+			return added
+		}
+		return strings.TrimRight(content, "\n") + "\n\n" + added
+	}
+
+	for i := headerLine + 1; i < len(lines) && !tomlHeader.MatchString(lines[i]) && !tomlArrayTableHeader.MatchString(lines[i]); i++ {
+		if !keyPattern.MatchString(lines[i]) {
+			continue
+		}
+
+		// Find the closing bracket, ignoring brackets inside quoted strings such as "uvicorn[standard]"
+		start := strings.Index(lines[i], "[")
+		rest := strings.Join(lines[i:], "\n")[start:]
+		end := tomlArrayEnd(rest)
+		if end < 0 {
+			return content
+		}
+
+		items := tomlArrayItems(rest[1:end])
+		prefix := strings.Join(lines[:i], "\n")
+		if prefix != "" {
+			prefix += "\n"
+		}
+		return prefix + lines[i][:start] + tomlList(edit(items)) + rest[end+1:]
+	}
+
+	items := edit(nil)
+	if len(items) == 0 {
+		return content
+	}
+	inserted := append(append(append([]string{}, lines[:headerLine+1]...), fmt.Sprintf("%s = %s", key, tomlList(items))), lines[headerLine+1:]...)
+	return strings.Join(inserted, "\n")
+}
+
+// tomlArrayItems returns the strings of an array body, skipping comments. Basic strings are
+// kept escaped as written and literal strings are escaped, so tomlList can quote either.
+func tomlArrayItems(s string) []string {
+	var items []string
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '#':
+			for i < len(s) && s[i] != '\n' {
+				i++
+			}
+		case '"', '\'':
+			match := tomlQuotedValue.FindStringSubmatchIndex(s[i:])
+			if match == nil || match[0] != 0 {
+				return items
+			}
+			if match[2] >= 0 {
+				items = append(items, s[i+match[2]:i+match[3]])
+			} else {
+				items = append(items, tomlLiteralEscaper.Replace(s[i+match[4]:i+match[5]]))
+			}
+			i += match[1] - 1
+		}
+	}
+	return items
+}
+
+// tomlArrayEnd returns the index of the bracket closing the array that s starts with,
+// or -1 when it is not closed
+func tomlArrayEnd(s string) int {
+	depth := 0
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == '\\' && quote == '"' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '#':
+			// Skip comments to the end of the line
+			for i < len(s) && s[i] != '\n' {
+				i++
+			}
+		case c == '[':
+			depth++
+		case c == ']':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
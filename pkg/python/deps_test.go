@@ -0,0 +1,183 @@
+package python
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTomlArrayEnd(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		want int
+	}{
+		{"empty", `[]`, 1},
+		{"single line", `["a", "b"] # done`, 9},
+		{"nested", `[["a"], ["b"]]`, 13},
+		{"bracket in string", `["uvicorn[standard]"]`, 20},
+		{"closing bracket in string", `["a]", 'b]']`, 11},
+		{"escaped quote", `["a\"]"]`, 7},
+		{"comment with bracket", "[\n  \"a\", # ]\n]", 13},
+		{"hash in string", `["a#b"]`, 6},
+		{"unclosed", "[\n  \"a\",\n", -1},
+		{"unclosed string", `["a]`, -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tomlArrayEnd(tt.s); got != tt.want {
+				t.Errorf("tomlArrayEnd(%q) = %d, want %d", tt.s, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEditTomlArray(t *testing.T) {
+	add := func(item string) func([]string) []string {
+		return func(items []string) []string { return append(items, item) }
+	}
+	tests := []struct {
+		name      string
+		content   string
+		table     string
+		key       string
+		edit      func([]string) []string
+		want      string
+		wantItems []string
+	}{
+		{
+			name:      "single line array",
+			content:   "[project]\nname = \"app\"\ndependencies = [\"requests\"]\n",
+			table:     "project",
+			key:       "dependencies",
+			edit:      add("rich"),
+			want:      "[project]\nname = \"app\"\ndependencies = [\n    \"requests\",\n    \"rich\",\n]\n",
+			wantItems: []string{"requests"},
+		},
+		{
+			name:      "multi-line array with trailing comma",
+			content:   "[project]\ndependencies = [\n    \"requests>=2\",\n    \"rich\",\n]\n\n[tool.black]\nline-length = 100\n",
+			table:     "project",
+			key:       "dependencies",
+			edit:      add("click"),
+			want:      "[project]\ndependencies = [\n    \"requests>=2\",\n    \"rich\",\n    \"click\",\n]\n\n[tool.black]\nline-length = 100\n",
+			wantItems: []string{"requests>=2", "rich"},
+		},
+		{
+			name:      "comments inside the array",
+			content:   "[project]\ndependencies = [\n    # HTTP \"client\" ]\n    \"requests\",  # pinned by \"ops\"\n]\n",
+			table:     "project",
+			key:       "dependencies",
+			edit:      func(items []string) []string { return items },
+			want:      "[project]\ndependencies = [\n    \"requests\",\n]\n",
+			wantItems: []string{"requests"},
+		},
+		{
+			name:      "strings containing brackets and hashes",
+			content:   "[project]\ndependencies = [\"uvicorn[standard]\", \"pkg @ https://example.com/pkg.zip#sha256=abc\", 'odd]name'] # end\n",
+			table:     "project",
+			key:       "dependencies",
+			edit:      func(items []string) []string { return items[1:] },
+			want:      "[project]\ndependencies = [\n    \"pkg @ https://example.com/pkg.zip#sha256=abc\",\n    \"odd]name\",\n] # end\n",
+			wantItems: []string{"uvicorn[standard]", "pkg @ https://example.com/pkg.zip#sha256=abc", "odd]name"},
+		},
+		{
+			name:      "literal string with a quote",
+			content:   "[project]\ndependencies = ['a; python_version > \"3.9\"']\n",
+			table:     "project",
+			key:       "dependencies",
+			edit:      func(items []string) []string { return items },
+			want:      "[project]\ndependencies = [\n    \"a; python_version > \\\"3.9\\\"\",\n]\n",
+			wantItems: []string{`a; python_version > \"3.9\"`},
+		},
+		{
+			name:      "optional dependencies",
+			content:   "[project]\ndependencies = []\n\n[project.optional-dependencies]\ndocs = [\"sphinx\"]\ndev = [\n    \"pytest\",\n]\n",
+			table:     "project.optional-dependencies",
+			key:       "dev",
+			edit:      add("ruff"),
+			want:      "[project]\ndependencies = []\n\n[project.optional-dependencies]\ndocs = [\"sphinx\"]\ndev = [\n    \"pytest\",\n    \"ruff\",\n]\n",
+			wantItems: []string{"pytest"},
+		},
+		{
+			name:    "missing key",
+			content: "[project.optional-dependencies]\ndocs = [\"sphinx\"]\n",
+			table:   "project.optional-dependencies",
+			key:     "dev",
+			edit:    add("pytest"),
+			want:    "[project.optional-dependencies]\ndev = [\n    \"pytest\",\n]\ndocs = [\"sphinx\"]\n",
+		},
+		{
+			name:    "key only in a later table",
+			content: "[project]\nname = \"app\"\n\n[tool.other]\ndependencies = [\"x\"]\n",
+			table:   "project",
+			key:     "dependencies",
+			edit:    add("rich"),
+			want:    "[project]\ndependencies = [\n    \"rich\",\n]\nname = \"app\"\n\n[tool.other]\ndependencies = [\"x\"]\n",
+		},
+		{
+			name:    "key after an array of tables",
+			content: "[project]\nname = \"app\"\n\n[[tool.mypy.overrides]]\ndependencies = [\"x\"]\n",
+			table:   "project",
+			key:     "dependencies",
+			edit:    add("rich"),
+			want:    "[project]\ndependencies = [\n    \"rich\",\n]\nname = \"app\"\n\n[[tool.mypy.overrides]]\ndependencies = [\"x\"]\n",
+		},
+		{
+			name:    "missing table",
+			content: "[project]\nname = \"app\"\n",
+			table:   "project.optional-dependencies",
+			key:     "dev",
+			edit:    add("pytest"),
+			want:    "[project]\nname = \"app\"\n\n[project.optional-dependencies]\ndev = [\n    \"pytest\",\n]\n",
+		},
+		{
+			name:    "missing table in an empty document",
+			content: "",
+			table:   "project",
+			key:     "dependencies",
+			edit:    add("rich"),
+			want:    "[project]\ndependencies = [\n    \"rich\",\n]\n",
+		},
+		{
+			name:    "missing table and nothing to add",
+			content: "[project]\nname = \"app\"\n",
+			table:   "project.optional-dependencies",
+			key:     "dev",
+			edit:    func(items []string) []string { return items },
+			want:    "[project]\nname = \"app\"\n",
+		},
+		{
+			name:      "removing the last item",
+			content:   "[project]\ndependencies = [\"rich\"]\n",
+			table:     "project",
+			key:       "dependencies",
+			edit:      func([]string) []string { return nil },
+			want:      "[project]\ndependencies = []\n",
+			wantItems: []string{"rich"},
+		},
+		{
+			name:      "unclosed array",
+			content:   "[project]\ndependencies = [\n    \"rich\",\n",
+			table:     "project",
+			key:       "dependencies",
+			edit:      add("click"),
+			want:      "[project]\ndependencies = [\n    \"rich\",\n",
+			wantItems: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotItems []string
+			got := editTomlArray(tt.content, tt.table, tt.key, func(items []string) []string {
+				gotItems = items
+				return tt.edit(items)
+			})
+			if got != tt.want {
+				t.Errorf("editTomlArray() =\n%s\nwant\n%s", got, tt.want)
+			}
+			if !reflect.DeepEqual(gotItems, tt.wantItems) {
+				t.Errorf("edit called with %q, want %q", gotItems, tt.wantItems)
+			}
+		})
+	}
+}
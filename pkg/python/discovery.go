@@ -0,0 +1,402 @@
+package python
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Candidate is a Python interpreter found on the machine
+type Candidate struct {
+	Command []string // Command used to find it, e.g. ["python3"]
+	Source  string   // Where it was found: PATH, py launcher, pyenv, asdf or install dir
+	Path    string   // Resolved executable path
+	Version string   // Full version, e.g. 3.11.7
+	Arch    string   // Pointer size, e.g. 64bit
+	InVenv  bool     // Whether the interpreter belongs to a virtual environment
+	HasPip  bool     // Whether pip is importable
+	HasVenv bool     // Whether venv and ensurepip are available to create virtual environments
+}
+
+// probeScript reports what devstation needs to know about an interpreter as JSON
+const probeScript = `import sys, json, platform, importlib.util as u
+print(json.dumps({
+    "version": "%d.%d.%d" % sys.version_info[:3],
+    "executable": sys.executable,
+    "arch": platform.architecture()[0],
+    "venv": sys.prefix != sys.base_prefix,
+    "pip": u.find_spec("pip") is not None,
+    "mkvenv": u.find_spec("venv") is not None and u.find_spec("ensurepip") is not None,
+}))`
+
+// probe runs an interpreter command and returns what it reports about itself
+func probe(source string, command ...string) (Candidate, error) {
+	cmd := exec.Command(command[0], append(command[1:], "-c", probeScript)...)
+	output, err := cmd.Output()
+	if err != nil {
+		return Candidate{}, err
+	}
+
+	var info struct {
+		Version    string `json:"version"`
+		Executable string `json:"executable"`
+		Arch       string `json:"arch"`
+		Venv       bool   `json:"venv"`
+		Pip        bool   `json:"pip"`
+		MkVenv     bool   `json:"mkvenv"`
+	}
+	if err := json.Unmarshal(output, &info); err != nil {
+		return Candidate{}, fmt.Errorf("unexpected output from %s: %v", command[0], err)
+	}
+
+	return Candidate{
+		Command: command,
+		Source:  source,
+		Path:    info.Executable,
+		Version: info.Version,
+		Arch:    info.Arch,
+		InVenv:  info.Venv,
+		HasPip:  info.Pip,
+		HasVenv: info.MkVenv,
+	}, nil
+}
+
+var (
+	discovered     []Candidate
+	discoveredOnce sync.Once
+)
+
+// Discover finds the Python interpreters on the machine: python3, python and versioned
+// executables on PATH, the Windows py launcher, pyenv and asdf installs and common
+// install directories. Results are cached for the rest of the run.
+func Discover() []Candidate {
+	discoveredOnce.Do(func() {
+		seen := make(map[string]bool)
+		for _, c := range candidateCommands() {
+			candidate, err := probe(c.source, c.command...)
+			if err != nil || candidate.Path == "" {
+				continue
+			}
+
+			key := candidate.Path
+			if !candidate.InVenv {
+				// Shims and symlinks resolve to the same base interpreter
+				if resolved, err := filepath.EvalSymlinks(key); err == nil {
+					key = resolved
+				}
+			}
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			discovered = append(discovered, candidate)
+		}
+	})
+	return discovered
+}
+
+// forgetDiscovered clears the cached discovery results, e.g. after installing Python
+func forgetDiscovered() {
+	discovered = nil
+	discoveredOnce = sync.Once{}
+}
+
+// candidateCommand is an interpreter invocation to probe
+type candidateCommand struct {
+	source  string
+	command []string
+}
+
+// candidateCommands lists the interpreter invocations to probe, in order of preference
+func candidateCommands() []candidateCommand {
+	var commands []candidateCommand
+	add := func(source string, command ...string) {
+		commands = append(commands, candidateCommand{source, command})
+	}
+
+	add("PATH", "python3")
+	add("PATH", "python")
+	for _, path := range versionedExecutablesOnPath() {
+		add("PATH", path)
+	}
+
+	if _, err := exec.LookPath("py"); err == nil {
+		for _, path := range pyLauncherPaths() {
+			add("py launcher", path)
+		}
+	}
+
+	for _, pattern := range pyenvPatterns() {
+		for _, path := range globExecutables(pattern) {
+			add("pyenv", path)
+		}
+	}
+	for _, pattern := range asdfPatterns() {
+		for _, path := range globExecutables(pattern) {
+			add("asdf", path)
+		}
+	}
+	for _, pattern := range installDirPatterns() {
+		for _, path := range globExecutables(pattern) {
+			add("install dir", path)
+		}
+	}
+
+	return commands
+}
+
+// versionedExecutableName matches executables such as python3.11 or python3.12.exe
+var versionedExecutableName = regexp.MustCompile(`^python3\.\d+(\.exe)?$`)
+
+// versionedExecutablesOnPath finds versioned python executables in the PATH directories
+func versionedExecutablesOnPath() []string {
+	var paths []string
+	for _, dir := range filepath.SplitList(os.Getenv("PATH")) {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			if versionedExecutableName.MatchString(entry.Name()) {
+				paths = append(paths, filepath.Join(dir, entry.Name()))
+			}
+		}
+	}
+	return paths
+}
+
+// pyLauncherPaths lists the interpreters known to the Windows py launcher
+func pyLauncherPaths() []string {
+	output, err := exec.Command("py", "--list-paths").Output()
+	if err != nil {
+		return nil
+	}
+
+	// Lines look like " -V:3.11 *        C:\Python311\python.exe" or " -3.11-64        C:\..."
+	var paths []string
+	for _, line := range strings.Split(string(output), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		path := fields[len(fields)-1]
+		if strings.HasSuffix(strings.ToLower(path), ".exe") {
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
+
+// pyenvPatterns returns glob patterns for interpreters installed by pyenv or pyenv-win
+func pyenvPatterns() []string {
+	root := os.Getenv("PYENV_ROOT")
+	if root == "" {
+		home, _ := os.UserHomeDir()
+		root = filepath.Join(home, ".pyenv")
+	}
+	if runtime.GOOS == "windows" {
+		return []string{filepath.Join(root, "pyenv-win", "versions", "*", "python.exe")}
+	}
+	return []string{filepath.Join(root, "versions", "*", "bin", "python3")}
+}
+
+// asdfPatterns returns glob patterns for interpreters installed by asdf
+func asdfPatterns() []string {
+	root := os.Getenv("ASDF_DATA_DIR")
+	if root == "" {
+		home, _ := os.UserHomeDir()
+		root = filepath.Join(home, ".asdf")
+	}
+	return []string{filepath.Join(root, "installs", "python", "*", "bin", "python3")}
+}
+
+// installDirPatterns returns glob patterns for the usual system-wide install locations
+func installDirPatterns() []string {
+	if runtime.GOOS == "windows" {
+		return []string{
+			filepath.Join(os.Getenv("LOCALAPPDATA"), "Programs", "Python", "Python3*", "python.exe"),
+			filepath.Join(os.Getenv("ProgramFiles"), "Python3*", "python.exe"),
+			`C:\Python3*\python.exe`,
+		}
+	}
+	return []string{
+		"/usr/bin/python3.*",
+		"/usr/local/bin/python3.*",
+		"/opt/homebrew/bin/python3.*",
+		"/Library/Frameworks/Python.framework/Versions/3.*/bin/python3",
+	}
+}
+
+// globExecutables expands a pattern, skipping config scripts and other non-interpreters
+func globExecutables(pattern string) []string {
+	matches, _ := filepath.Glob(pattern)
+
+	var paths []string
+	for _, path := range matches {
+		base := filepath.Base(path)
+		if strings.HasSuffix(base, "-config") || strings.HasSuffix(base, "m") {
+			continue
+		}
+		paths = append(paths, path)
+	}
+	return paths
+}
+
+// Constraint is a set of version requirements such as ">=3.9,<3.13" or "3.11"
+type Constraint []constraintClause
+
+type constraintClause struct {
+	op      string
+	version []int
+}
+
+// ParseConstraint parses a comma-separated version constraint. A bare version like "3.11"
+// matches any 3.11.x release; an empty constraint matches every version.
+func ParseConstraint(spec string) (Constraint, error) {
+	var constraint Constraint
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		op := "=="
+		for _, candidate := range []string{">=", "<=", "==", "!=", ">", "<"} {
+			if strings.HasPrefix(part, candidate) {
+				op = candidate
+				part = strings.TrimSpace(part[len(candidate):])
+				break
+			}
+		}
+
+		version, err := parseVersion(part)
+		if err != nil {
+			return nil, fmt.Errorf("invalid version constraint %q: %v", spec, err)
+		}
+		constraint = append(constraint, constraintClause{op, version})
+	}
+	return constraint, nil
+}
+
+// Matches reports whether a version satisfies every clause of the constraint
+func (c Constraint) Matches(version string) bool {
+	v, err := parseVersion(version)
+	if err != nil {
+		return false
+	}
+
+	for _, clause := range c {
+		// Compare only as many components as the clause specifies, so "==3.11" matches 3.11.7
+		cmp := compareVersions(v, clause.version, len(clause.version))
+		ok := false
+		switch clause.op {
+		case "==":
+			ok = cmp == 0
+		case "!=":
+			ok = cmp != 0
+		case ">=":
+			ok = cmp >= 0
+		case "<=":
+			ok = cmp <= 0
+		case ">":
+			ok = cmp > 0
+		case "<":
+			ok = cmp < 0
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// parseVersion splits a dotted version into its numeric components
+func parseVersion(version string) ([]int, error) {
+	var parts []int
+	for _, field := range strings.Split(version, ".") {
+		n, err := strconv.Atoi(field)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a version number", version)
+		}
+		parts = append(parts, n)
+	}
+	return parts, nil
+}
+
+// compareVersions compares the first n components of two versions
+func compareVersions(a, b []int, n int) int {
+	for i := 0; i < n; i++ {
+		var x, y int
+		if i < len(a) {
+			x = a[i]
+		}
+		if i < len(b) {
+			y = b[i]
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// BestMatch picks the most suitable discovered interpreter for a version constraint:
+// interpreters outside virtual environments that can create venvs and have pip are
+// preferred, then 64-bit builds, then the newest version.
+func BestMatch(spec string) (Candidate, error) {
+	constraint, err := ParseConstraint(spec)
+	if err != nil {
+		return Candidate{}, err
+	}
+
+	var matches []Candidate
+	for _, candidate := range Discover() {
+		if constraint.Matches(candidate.Version) {
+			matches = append(matches, candidate)
+		}
+	}
+	if len(matches) == 0 {
+		if spec == "" {
+			return Candidate{}, fmt.Errorf("no Python interpreter found")
+		}
+		return Candidate{}, fmt.Errorf("no Python interpreter matching %s found", spec)
+	}
+
+	score := func(c Candidate) int {
+		s := 0
+		if !c.InVenv {
+			s += 8
+		}
+		if c.HasVenv {
+			s += 4
+		}
+		if c.HasPip {
+			s += 2
+		}
+		if c.Arch == "64bit" {
+			s++
+		}
+		return s
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		if si, sj := score(matches[i]), score(matches[j]); si != sj {
+			return si > sj
+		}
+		vi, _ := parseVersion(matches[i].Version)
+		vj, _ := parseVersion(matches[j].Version)
+		return compareVersions(vi, vj, 3) > 0
+	})
+
+	return matches[0], nil
+}
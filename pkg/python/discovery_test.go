@@ -0,0 +1,73 @@
+package python
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseConstraint(t *testing.T) {
+	tests := []struct {
+		spec    string
+		want    Constraint
+		wantErr bool
+	}{
+		{"", nil, false},
+		{"3.11", Constraint{{"==", []int{3, 11}}}, false},
+		{">=3.9,<3.13", Constraint{{">=", []int{3, 9}}, {"<", []int{3, 13}}}, false},
+		{" >= 3.11.5 , != 3.12 ", Constraint{{">=", []int{3, 11, 5}}, {"!=", []int{3, 12}}}, false},
+		{"<=3", Constraint{{"<=", []int{3}}}, false},
+		{"3.x", nil, true},
+		{">=", nil, true},
+		{"~=3.11", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			got, err := ParseConstraint(tt.spec)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseConstraint(%q) error = %v, wantErr %v", tt.spec, err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseConstraint(%q) = %v, want %v", tt.spec, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConstraintMatches(t *testing.T) {
+	tests := []struct {
+		spec    string
+		version string
+		want    bool
+	}{
+		{"", "3.8.0", true},
+		{"3.11", "3.11.9", true},
+		{"3.11", "3.11", true},
+		{"3.11", "3.12.0", false},
+		{"==3.11.5", "3.11.5", true},
+		{"==3.11.5", "3.11.6", false},
+		{">=3.11.5", "3.11.9", true},
+		{">=3.11.5", "3.11.4", false},
+		{">=3.11.5", "3.12.0", true},
+		{">=3.9,<3.13", "3.12.7", true},
+		{">=3.9,<3.13", "3.13.0", false},
+		{">=3.9,<3.13", "3.8.18", false},
+		{"!=3.12", "3.12.1", false},
+		{"!=3.12", "3.11.1", true},
+		{">3.11", "3.11.9", false},
+		{">3.11", "3.12.0", true},
+		{"<=3.11", "3.11.9", true},
+		{"<3.11.5", "3.11.4", true},
+		{"3.11", "not-a-version", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.spec+"/"+tt.version, func(t *testing.T) {
+			constraint, err := ParseConstraint(tt.spec)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := constraint.Matches(tt.version); got != tt.want {
+				t.Errorf("ParseConstraint(%q).Matches(%q) = %v, want %v", tt.spec, tt.version, got, tt.want)
+			}
+		})
+	}
+}
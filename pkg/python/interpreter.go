@@ -0,0 +1,124 @@
+package python
+
+import (
+	"fmt"
+	"strings"
+
+	"devstation-cli/pkg/config"
+	"devstation-cli/pkg/installer"
+)
+
+// QueryInterpreter runs a Python command and returns its version and executable path
+func QueryInterpreter(command string, args ...string) (config.Interpreter, error) {
+	candidate, err := probe("command", append([]string{command}, args...)...)
+	if err != nil {
+		return config.Interpreter{}, err
+	}
+	return config.Interpreter{Version: candidate.Version, Path: candidate.Path}, nil
+}
+
+// FindInterpreter locates an installed interpreter for a version constraint such as
+// "3.11", checking the registered interpreters before discovering installed ones
+func FindInterpreter(version string) (config.Interpreter, error) {
+	constraint, err := ParseConstraint(version)
+	if err != nil {
+		return config.Interpreter{}, err
+	}
+	for _, interpreter := range config.Current().Python.Interpreters {
+		if interpreter = fullVersion(interpreter); constraint.Matches(interpreter.Version) {
+			return interpreter, nil
+		}
+	}
+
+	candidate, err := BestMatch(version)
+	if err != nil {
+		return config.Interpreter{}, err
+	}
+	return config.Interpreter{Version: candidate.Version, Path: candidate.Path}, nil
+}
+
+// fullVersion re-probes a registered interpreter whose version was recorded as major.minor by
+// earlier releases, so constraints like ">=3.11.5" compare against its patch release. An
+// interpreter that no longer runs keeps its recorded version.
+func fullVersion(interpreter config.Interpreter) config.Interpreter {
+	if strings.Count(interpreter.Version, ".") >= 2 {
+		return interpreter
+	}
+	if candidate, err := probe("registered", interpreter.Path); err == nil {
+		interpreter.Version = candidate.Version
+	}
+	return interpreter
+}
+
+// pythonPackageName returns the system package providing a specific Python version
+func pythonPackageName(pm installer.PackageManager, version string) string {
+	if version == "" {
+		return "python"
+	}
+
+	switch installer.ManagerName(pm) {
+	case "winget":
+		return "Python.Python." + version
+	case "choco", "scoop":
+		return "python" + strings.ReplaceAll(version, ".", "")
+	case "brew":
+		return "python@" + version
+	case "apt":
+		return "python" + version
+	}
+	return "python"
+}
+
+// InstallVersion installs a specific Python version side by side with any others and
+// registers the interpreter in the devstation configuration
+func (p *PythonSetup) InstallVersion(version string) (config.Interpreter, error) {
+	if interpreter, err := FindInterpreter(version); err == nil {
+		fmt.Printf("✓ Python %s is already installed at %s\n", version, interpreter.Path)
+		return interpreter, RegisterInterpreter(interpreter, false)
+	}
+
+	if err := p.PackageManager.Install(pythonPackageName(p.PackageManager, version)); err != nil {
+		return config.Interpreter{}, fmt.Errorf("failed to install Python %s: %v", version, err)
+	}
+	forgetDiscovered()
+
+	interpreter, err := FindInterpreter(version)
+	if err != nil {
+		return config.Interpreter{}, fmt.Errorf("Python %s was installed but could not be located; open a new terminal and run 'devstation python use %s'", version, version)
+	}
+	return interpreter, RegisterInterpreter(interpreter, false)
+}
+
+// RegisterInterpreter records an interpreter in the configuration, making it the default
+// when makeDefault is set or when no default has been chosen yet
+func RegisterInterpreter(interpreter config.Interpreter, makeDefault bool) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+
+	cfg.Python.AddInterpreter(interpreter)
+	if makeDefault || cfg.Python.Default == "" {
+		cfg.Python.Default = interpreter.Path
+	}
+	if err := cfg.Save(); err != nil {
+		return err
+	}
+
+	// Keep the configuration used by this run in sync
+	current := config.Current()
+	current.Python = cfg.Python
+	return nil
+}
+
+// DefaultInterpreter returns the interpreter PythonSetup uses when none is selected: the
+// configured default, else the best discovered interpreter, else bare "python"
+func DefaultInterpreter() string {
+	if path := config.Current().Python.Default; path != "" {
+		return path
+	}
+	if candidate, err := BestMatch(""); err == nil {
+		return candidate.Path
+	}
+	return "python"
+}
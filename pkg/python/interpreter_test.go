@@ -0,0 +1,113 @@
+package python
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"devstation-cli/pkg/config"
+)
+
+// fakeInterpreter writes a script that answers devstation's probe like a Python of version
+func fakeInterpreter(t *testing.T, version string) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("fake interpreters are shell scripts")
+	}
+	path := filepath.Join(t.TempDir(), "python3")
+	script := "#!/bin/sh\necho '{\"version\": \"" + version + "\", \"executable\": \"" + path + "\", \"arch\": \"64bit\", \"pip\": true, \"mkvenv\": true}'\n"
+	if err := os.WriteFile(path, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestFindInterpreterRegistered(t *testing.T) {
+	legacy := fakeInterpreter(t, "3.11.9")
+	tests := []struct {
+		name       string
+		registered []config.Interpreter
+		spec       string
+		want       config.Interpreter
+	}{
+		{
+			name:       "full version",
+			registered: []config.Interpreter{{Version: "3.11.9", Path: "/opt/python3.11/bin/python3"}},
+			spec:       ">=3.11.5",
+			want:       config.Interpreter{Version: "3.11.9", Path: "/opt/python3.11/bin/python3"},
+		},
+		{
+			name:       "major.minor version is probed",
+			registered: []config.Interpreter{{Version: "3.11", Path: legacy}},
+			spec:       ">=3.11.5",
+			want:       config.Interpreter{Version: "3.11.9", Path: legacy},
+		},
+		{
+			name: "first match wins",
+			registered: []config.Interpreter{
+				{Version: "3.10.14", Path: "/opt/python3.10/bin/python3"},
+				{Version: "3.12.3", Path: "/opt/python3.12/bin/python3"},
+				{Version: "3.12.4", Path: "/usr/local/bin/python3.12"},
+			},
+			spec: "3.12",
+			want: config.Interpreter{Version: "3.12.3", Path: "/opt/python3.12/bin/python3"},
+		},
+	}
+
+	current := config.Current()
+	saved := current.Python
+	defer func() { current.Python = saved }()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			current.Python.Interpreters = tt.registered
+			got, err := FindInterpreter(tt.spec)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("FindInterpreter(%q) = %+v, want %+v", tt.spec, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQueryInterpreter(t *testing.T) {
+	path := fakeInterpreter(t, "3.12.4")
+	got, err := QueryInterpreter(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (config.Interpreter{Version: "3.12.4", Path: path}); got != want {
+		t.Errorf("QueryInterpreter() = %+v, want %+v", got, want)
+	}
+}
+
+func TestFullVersionUnreachable(t *testing.T) {
+	interpreter := config.Interpreter{Version: "3.11", Path: filepath.Join(t.TempDir(), "missing")}
+	if got := fullVersion(interpreter); got != interpreter {
+		t.Errorf("fullVersion() = %+v, want the recorded %+v", got, interpreter)
+	}
+}
+
+func TestPythonResolvesDefaultLazily(t *testing.T) {
+	current := config.Current()
+	saved := current.Python
+	defer func() { current.Python = saved }()
+	current.Python.Default = "/opt/python3.12/bin/python3"
+
+	setup := NewPythonSetup(nil)
+	if setup.Interpreter != "" {
+		t.Errorf("NewPythonSetup().Interpreter = %q, want it unresolved", setup.Interpreter)
+	}
+	if got := setup.Python(); got != current.Python.Default {
+		t.Errorf("Python() = %q, want the configured default %q", got, current.Python.Default)
+	}
+
+	override := NewPythonSetup(nil)
+	override.Interpreter = "/usr/bin/python3.11"
+	current.Python.Default = "/opt/other/python3"
+	if got := override.Python(); got != "/usr/bin/python3.11" {
+		t.Errorf("Python() = %q, want the chosen interpreter", got)
+	}
+}
@@ -0,0 +1,146 @@
+package python
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+
+	"devstation-cli/pkg/project"
+)
+
+// Kernel is a Jupyter kernel spec registered for the user
+type Kernel struct {
+	Name        string // Kernel name, the spec's directory name
+	DisplayName string // Name shown in Jupyter's kernel picker
+	Dir         string // Kernel spec directory
+	Python      string // Interpreter the kernel runs
+	Stale       bool   // The interpreter no longer exists, e.g. its venv was deleted
+}
+
+// KernelName derives a Jupyter kernel name from a project directory, e.g. "My Project" -> "my-project"
+func KernelName(projectDir string) string {
+	return project.DistributionName(filepath.Base(projectDir))
+}
+
+// KernelDisplayName returns the name a project's kernel is shown with in Jupyter
+func KernelDisplayName(projectDir string) string {
+	return fmt.Sprintf("Python (%s)", filepath.Base(projectDir))
+}
+
+// KernelsDir returns the directory holding the user's Jupyter kernel specs, honouring
+// JUPYTER_DATA_DIR like Jupyter itself
+func KernelsDir() (string, error) {
+	if dir := os.Getenv("JUPYTER_DATA_DIR"); dir != "" {
+		return filepath.Join(dir, "kernels"), nil
+	}
+
+	switch runtime.GOOS {
+	case "windows":
+		return filepath.Join(os.Getenv("APPDATA"), "jupyter", "kernels"), nil
+	case "darwin":
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		return filepath.Join(home, "Library", "Jupyter", "kernels"), nil
+	}
+
+	if data := os.Getenv("XDG_DATA_HOME"); data != "" {
+		return filepath.Join(data, "jupyter", "kernels"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".local", "share", "jupyter", "kernels"), nil
+}
+
+// RegisterKernel installs ipykernel into the venv if needed and registers a user kernel
+// spec that runs the venv's interpreter
+func (p *PythonSetup) RegisterKernel(venv Venv, name, displayName string) error {
+	if !venv.Exists() {
+		return fmt.Errorf("no virtual environment at %s; run 'devstation venv create' first", venv.Dir)
+	}
+
+	if exec.Command(venv.Python(), "-c", "import ipykernel").Run() != nil {
+		fmt.Println("Installing ipykernel into the virtual environment...")
+		if err := p.ForVenv(venv).runPipInstall("ipykernel"); err != nil {
+			return fmt.Errorf("failed to install ipykernel: %v", err)
+		}
+	}
+
+	cmd := exec.Command(venv.Python(), "-m", "ipykernel", "install", "--user", "--name", name, "--display-name", displayName)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to register kernel %s: %v", name, err)
+	}
+	return nil
+}
+
+// Kernels lists the user's Jupyter kernel specs, marking those whose interpreter is gone
+func Kernels() ([]Kernel, error) {
+	dir, err := KernelsDir()
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var kernels []Kernel
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		specDir := filepath.Join(dir, entry.Name())
+		content, err := os.ReadFile(filepath.Join(specDir, "kernel.json"))
+		if err != nil {
+			continue
+		}
+
+		var spec struct {
+			Argv        []string `json:"argv"`
+			DisplayName string   `json:"display_name"`
+		}
+		if err := json.Unmarshal(content, &spec); err != nil || len(spec.Argv) == 0 {
+			continue
+		}
+
+		kernel := Kernel{Name: entry.Name(), DisplayName: spec.DisplayName, Dir: specDir, Python: spec.Argv[0]}
+		// Only absolute interpreter paths can be checked; bare commands are resolved on PATH
+		if filepath.IsAbs(kernel.Python) && !fileExists(kernel.Python) {
+			kernel.Stale = true
+		}
+		kernels = append(kernels, kernel)
+	}
+	return kernels, nil
+}
+
+// PruneKernels removes the kernel specs whose interpreter no longer exists and returns them
+func PruneKernels() ([]Kernel, error) {
+	kernels, err := Kernels()
+	if err != nil {
+		return nil, err
+	}
+
+	var removed []Kernel
+	for _, kernel := range kernels {
+		if !kernel.Stale {
+			continue
+		}
+		if err := os.RemoveAll(kernel.Dir); err != nil {
+			return removed, fmt.Errorf("failed to remove kernel %s: %v", kernel.Name, err)
+		}
+		removed = append(removed, kernel)
+	}
+	return removed, nil
+}
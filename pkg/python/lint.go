@@ -0,0 +1,142 @@
+package python
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Linter is a code quality tool devstation runs in a project's venv
+type Linter struct {
+	Name    string
+	Module  string   // Module run with "python -m"
+	Args    []string // Arguments for checking
+	FixArgs []string // Arguments for fixing problems in place; nil when the tool cannot fix
+	// configured reports whether the project has configuration for the linter
+	configured func(projectDir string) bool
+}
+
+// Linters lists the supported linters in the order they run
+var Linters = []Linter{
+	{
+		Name:       "ruff",
+		Module:     "ruff",
+		Args:       []string{"check", "."},
+		FixArgs:    []string{"check", "--fix", "."},
+		configured: hasConfig("ruff.toml", ".ruff.toml", "pyproject.toml:[tool.ruff"),
+	},
+	{
+		Name:       "black",
+		Module:     "black",
+		Args:       []string{"--check", "--diff", "."},
+		FixArgs:    []string{"."},
+		configured: hasConfig("pyproject.toml:[tool.black"),
+	},
+	{
+		Name:       "flake8",
+		Module:     "flake8",
+		configured: hasConfig(".flake8", "setup.cfg:[flake8]", "tox.ini:[flake8]"),
+	},
+	{
+		Name:       "mypy",
+		Module:     "mypy",
+		configured: hasConfig("mypy.ini", ".mypy.ini", "pyproject.toml:[tool.mypy", "setup.cfg:[mypy]"),
+	},
+}
+
+// LintResult is the outcome of running one linter
+type LintResult struct {
+	Linter  string
+	Passed  bool
+	Skipped string // Why the linter did not run, if it did not
+}
+
+// hasConfig returns a check for linter configuration: a file that must exist, or
+// "file:marker" for a file that must contain the marker
+func hasConfig(specs ...string) func(string) bool {
+	return func(projectDir string) bool {
+		for _, spec := range specs {
+			name, marker := spec, ""
+			if i := strings.Index(spec, ":"); i >= 0 {
+				name, marker = spec[:i], spec[i+1:]
+			}
+			content, err := os.ReadFile(filepath.Join(projectDir, name))
+			if err == nil && strings.Contains(string(content), marker) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Lint runs every linter the project has configuration for inside its venv, fixing
+// problems in place where the linter supports it when fix is set
+func Lint(projectDir string, venv Venv, fix bool) ([]LintResult, error) {
+	if !venv.Exists() {
+		return nil, fmt.Errorf("no virtual environment at %s; run 'devstation venv create' first", venv.Dir)
+	}
+
+	var results []LintResult
+	for _, linter := range Linters {
+		result := LintResult{Linter: linter.Name}
+		switch {
+		case !linter.configured(projectDir):
+			result.Skipped = "not configured"
+		case exec.Command(venv.Python(), "-c", "import "+linter.Module).Run() != nil:
+			result.Skipped = "not installed in the venv"
+		}
+		if result.Skipped != "" {
+			results = append(results, result)
+			continue
+		}
+
+		args := linter.Args
+		if fix && linter.FixArgs != nil {
+			args = linter.FixArgs
+		}
+		fmt.Printf("=== %s ===\n", linter.Name)
+		cmd := venv.Command(venv.Python(), append([]string{"-m", linter.Module}, args...)...)
+		cmd.Dir = projectDir
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		result.Passed = cmd.Run() == nil
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// preCommitDevDependencies are added to the dev extra of projects created with pre-commit hooks
+var preCommitDevDependencies = []string{"flake8", "pre-commit"}
+
+// InstallPreCommitHooks installs the project's pre-commit hooks into its git repository,
+// initializing the repository first when the project is not inside one
+func InstallPreCommitHooks(projectDir string, venv Venv) error {
+	// Commands below run inside projectDir, so the venv path must not be relative to the caller
+	venvDir, err := filepath.Abs(venv.Dir)
+	if err != nil {
+		return err
+	}
+	venv = Venv{Dir: venvDir}
+
+	if exec.Command("git", "-C", projectDir, "rev-parse", "--is-inside-work-tree").Run() != nil {
+		fmt.Println("Initializing git repository...")
+		if output, err := exec.Command("git", "init", projectDir).CombinedOutput(); err != nil {
+			return fmt.Errorf("git init failed: %v: %s", err, strings.TrimSpace(string(output)))
+		}
+	}
+
+	if exec.Command(venv.Python(), "-c", "import pre_commit").Run() != nil {
+		return fmt.Errorf("pre-commit is not installed in the virtual environment")
+	}
+
+	cmd := venv.Command(venv.Python(), "-m", "pre_commit", "install")
+	cmd.Dir = projectDir
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("pre-commit install failed: %v", err)
+	}
+	return nil
+}
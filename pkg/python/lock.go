@@ -0,0 +1,174 @@
+package python
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Requirement input files compiled into lock files by pip-compile
+const (
+	RequirementsIn    = "requirements.in"
+	DevRequirementsIn = "requirements-dev.in"
+)
+
+// lockFile returns the lock file pip-compile generates from a requirements input file
+func lockFile(inputFile string) string {
+	return strings.TrimSuffix(inputFile, ".in") + ".txt"
+}
+
+// DetectProjectManager works out which project manager a project uses from its files:
+// Pipfile for Pipenv, poetry.toml, poetry.lock or [tool.poetry] for Poetry, uv.lock or
+// [tool.uv] for uv, [tool.hatch.envs] for Hatch, and pip otherwise
+func DetectProjectManager(projectDir string) string {
+	has := func(name string) bool { return fileExists(filepath.Join(projectDir, name)) }
+
+	if has("Pipfile") {
+		return "pipenv"
+	}
+	if has("poetry.toml") || has("poetry.lock") {
+		return "poetry"
+	}
+	if has("uv.lock") {
+		return "uv"
+	}
+
+	pyproject, _ := os.ReadFile(filepath.Join(projectDir, "pyproject.toml"))
+	switch {
+	case strings.Contains(string(pyproject), "[tool.poetry"):
+		return "poetry"
+	case strings.Contains(string(pyproject), "[tool.uv"):
+		return "uv"
+	case strings.Contains(string(pyproject), "[tool.hatch.envs"):
+		return "hatch"
+	}
+	return DefaultProjectManager
+}
+
+// Lock compiles the project's requirements.in and requirements-dev.in into lock files
+// with hashes using pip-compile in the project's venv. Projects using another manager
+// are locked with that manager's own command.
+func (p *PythonSetup) Lock(projectDir string, venv Venv, upgrade bool) error {
+	managerName := DetectProjectManager(projectDir)
+	if managerName != DefaultProjectManager {
+		manager := ProjectManagers[managerName]
+		if len(manager.Lock) == 0 {
+			return fmt.Errorf("%s projects have no lock file", managerName)
+		}
+		fmt.Printf("Locking with %s...\n", managerName)
+		return p.runProjectManager(manager, projectDir, manager.Lock)
+	}
+
+	inputs := requirementInputs(projectDir)
+	if len(inputs) == 0 {
+		return fmt.Errorf("no %s or %s in %s", RequirementsIn, DevRequirementsIn, projectDir)
+	}
+	if err := p.ensurePipTools(venv); err != nil {
+		return err
+	}
+
+	// requirements.in is compiled first because requirements-dev.in is constrained by its lock file
+	for _, input := range inputs {
+		output := lockFile(input)
+		fmt.Printf("Compiling %s -> %s...\n", input, output)
+		args := []string{"-m", "piptools", "compile", "--generate-hashes", "--allow-unsafe", "--strip-extras", "--quiet", "--output-file", output}
+		if upgrade {
+			args = append(args, "--upgrade")
+		}
+		if err := p.runVenvPython(venv, projectDir, append(args, input)...); err != nil {
+			return fmt.Errorf("pip-compile failed for %s: %v", input, err)
+		}
+	}
+	return nil
+}
+
+// Sync makes the project's venv match its lock files exactly with pip-sync, removing
+// anything not locked, then reinstalls the project itself in editable mode. Projects
+// using another manager are synced with that manager's install command.
+func (p *PythonSetup) Sync(projectDir string, venv Venv, dev bool) error {
+	managerName := DetectProjectManager(projectDir)
+	if managerName != DefaultProjectManager {
+		manager := ProjectManagers[managerName]
+		fmt.Printf("Syncing with %s...\n", managerName)
+		return p.runProjectManager(manager, projectDir, manager.Install)
+	}
+
+	var locks []string
+	for _, input := range requirementInputs(projectDir) {
+		if input == DevRequirementsIn && !dev {
+			continue
+		}
+		lock := lockFile(input)
+		if !fileExists(filepath.Join(projectDir, lock)) {
+			return fmt.Errorf("%s has not been generated; run 'devstation lock' first", lock)
+		}
+		locks = append(locks, lock)
+	}
+	if len(locks) == 0 {
+		return fmt.Errorf("no lock files in %s; run 'devstation lock' first", projectDir)
+	}
+	if err := p.ensurePipTools(venv); err != nil {
+		return err
+	}
+
+	fmt.Printf("Syncing %s with %s...\n", venv.Dir, strings.Join(locks, ", "))
+	if err := p.runVenvPython(venv, projectDir, append([]string{"-m", "piptools", "sync"}, locks...)...); err != nil {
+		return fmt.Errorf("pip-sync failed: %v", err)
+	}
+
+	// pip-sync removes the editable project install because it is not in the lock files
+	if fileExists(filepath.Join(projectDir, "pyproject.toml")) || fileExists(filepath.Join(projectDir, "setup.py")) {
+		fmt.Println("Installing project in editable mode...")
+		if err := p.ForVenv(venv).runPipInstall("--no-deps", "-e", projectDir); err != nil {
+			return fmt.Errorf("failed to install project: %v", err)
+		}
+	}
+	return nil
+}
+
+// requirementInputs returns the requirement input files present in the project, in
+// compile order
+func requirementInputs(projectDir string) []string {
+	var inputs []string
+	for _, input := range []string{RequirementsIn, DevRequirementsIn} {
+		if fileExists(filepath.Join(projectDir, input)) {
+			inputs = append(inputs, input)
+		}
+	}
+	return inputs
+}
+
+// ensurePipTools installs pip-tools into the venv unless it is already there; it runs from
+// the project's venv so locks are resolved for the project's interpreter
+func (p *PythonSetup) ensurePipTools(venv Venv) error {
+	return p.ensureVenvModule(venv, "piptools", "pip-tools")
+}
+
+// ensureVenvModule installs a package into the project's venv unless its module can already be imported
+func (p *PythonSetup) ensureVenvModule(venv Venv, module, pkg string) error {
+	if !venv.Exists() {
+		return fmt.Errorf("no virtual environment at %s; run 'devstation venv create' first", venv.Dir)
+	}
+	if exec.Command(venv.Python(), "-c", "import "+module).Run() == nil {
+		return nil
+	}
+
+	fmt.Printf("Installing %s into the virtual environment...\n", pkg)
+	if err := p.ForVenv(venv).runPipInstall(pkg); err != nil {
+		return fmt.Errorf("failed to install %s: %v", pkg, err)
+	}
+	return nil
+}
+
+// runVenvPython runs the venv's interpreter in the project directory with the configured
+// package index settings
+func (p *PythonSetup) runVenvPython(venv Venv, projectDir string, args ...string) error {
+	cmd := exec.Command(venv.Python(), args...)
+	cmd.Dir = projectDir
+	cmd.Env = append(os.Environ(), p.pipIndexEnv()...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
@@ -0,0 +1,179 @@
+package python
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ProjectManager describes a tool that creates a project's virtual environment and
+// manages its lock file
+type ProjectManager struct {
+	Description string
+	Package     string   // Package installed as an isolated tool; empty for pip
+	Backend     string   // Build backend used when none is selected
+	Install     []string // Creates the venv and installs the project with its dev dependencies
+	Lock        []string // Refreshes the lock file; empty when the manager has none
+	Run         []string // Prefix that runs a command inside the project's environment
+	Env         []string // Extra environment for the manager's commands
+}
+
+// DefaultProjectManager is used when no project manager is selected
+const DefaultProjectManager = "pip"
+
+// ProjectManagers lists the supported project managers by name
+var ProjectManagers = map[string]ProjectManager{
+	"pip": {
+		Description: "requirements.txt and a venv created with python -m venv",
+		Backend:     DefaultBuildBackend,
+	},
+	"poetry": {
+		Description: "Poetry with poetry.lock and an in-project .venv",
+		Package:     "poetry",
+		Backend:     "poetry",
+		Install:     []string{"poetry", "install", "--all-extras"},
+		Lock:        []string{"poetry", "lock"},
+		Run:         []string{"poetry", "run"},
+	},
+	"uv": {
+		Description: "uv with uv.lock and an in-project .venv",
+		Package:     "uv",
+		Backend:     "hatchling",
+		Install:     []string{"uv", "sync", "--all-extras"},
+		Lock:        []string{"uv", "lock"},
+		Run:         []string{"uv", "run"},
+	},
+	"hatch": {
+		Description: "Hatch environments with the dev extra, in .venv",
+		Package:     "hatch",
+		Backend:     "hatchling",
+		Install:     []string{"hatch", "env", "create"},
+		Run:         []string{"hatch", "run"},
+	},
+	"pipenv": {
+		Description: "Pipenv with Pipfile, Pipfile.lock and an in-project .venv",
+		Package:     "pipenv",
+		Backend:     DefaultBuildBackend,
+		Install:     []string{"pipenv", "install", "--dev"},
+		Lock:        []string{"pipenv", "lock"},
+		Run:         []string{"pipenv", "run"},
+		Env:         []string{"PIPENV_VENV_IN_PROJECT=1"},
+	},
+}
+
+// ProjectManagerNames returns the names of the supported project managers in sorted order
+func ProjectManagerNames() []string {
+	var names []string
+	for name := range ProjectManagers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// lookupProjectManager returns a project manager by name, defaulting to DefaultProjectManager
+func lookupProjectManager(name string) (ProjectManager, error) {
+	if name == "" {
+		name = DefaultProjectManager
+	}
+	manager, ok := ProjectManagers[name]
+	if !ok {
+		return ProjectManager{}, fmt.Errorf("unknown project manager %q; expected one of %s", name, strings.Join(ProjectManagerNames(), ", "))
+	}
+	return manager, nil
+}
+
+// EnsureProjectManager makes a project manager's command available, installing it into an
+// isolated tool environment when it is not already on PATH or among the tool shims
+func (p *PythonSetup) EnsureProjectManager(name string) error {
+	manager, err := lookupProjectManager(name)
+	if err != nil {
+		return err
+	}
+	if manager.Package == "" || managerExecutable(manager) != "" {
+		return nil
+	}
+
+	fmt.Printf("Installing %s...\n", manager.Package)
+	if err := p.InstallTool(manager.Package); err != nil {
+		return fmt.Errorf("failed to install %s: %v", manager.Package, err)
+	}
+	return nil
+}
+
+// managerExecutable locates a project manager's command on PATH or in the shim directory
+func managerExecutable(manager ProjectManager) string {
+	command := manager.Install[0]
+	if path, err := exec.LookPath(command); err == nil {
+		return path
+	}
+	if shimDir, err := ShimDir(); err == nil {
+		for _, name := range []string{command, command + ".cmd"} {
+			if path := filepath.Join(shimDir, name); fileExists(path) {
+				return path
+			}
+		}
+	}
+	return ""
+}
+
+// runProjectManager runs one of a project manager's commands in the project directory
+func (p *PythonSetup) runProjectManager(manager ProjectManager, projectDir string, args []string) error {
+	executable := managerExecutable(manager)
+	if executable == "" {
+		return fmt.Errorf("%s is not installed; run 'devstation tools install %s'", args[0], manager.Package)
+	}
+
+	cmd := exec.Command(executable, args[1:]...)
+	cmd.Dir = projectDir
+	cmd.Env = append(append(os.Environ(), p.pipIndexEnv()...), manager.Env...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%s failed: %v", strings.Join(args, " "), err)
+	}
+	return nil
+}
+
+// managerWorkflow returns the project README section describing the manager's workflow,
+// without a trailing newline
+func managerWorkflow(managerName string, manager ProjectManager) string {
+	var lines []string
+	if managerName == DefaultProjectManager {
+		lines = []string{
+			"devstation lock   # pin requirements*.in into hashed requirements*.txt",
+			"devstation sync   # make the venv match the lock files",
+			"devstation venv exec -- pytest",
+		}
+	} else {
+		install := strings.Join(manager.Install, " ")
+		lock := strings.Join(manager.Lock, " ")
+		width := len(install)
+		if len(lock) > width {
+			width = len(lock)
+		}
+		lines = append(lines, fmt.Sprintf("%-*s  # create .venv and install the project", width, install))
+		if lock != "" {
+			lines = append(lines, fmt.Sprintf("%-*s  # refresh the lock file", width, lock))
+		}
+		lines = append(lines, strings.Join(append(append([]string{}, manager.Run...), "pytest"), " "))
+	}
+	return "## Development\n\n```bash\n" + strings.Join(lines, "\n") + "\n```"
+}
+
+// splitRequirement splits a requirement such as "httpx>=0.27" into its name and version
+// specifier, using "*" when there is no specifier
+func splitRequirement(requirement string) (string, string) {
+	i := strings.IndexAny(requirement, "<>=!~[; ")
+	if i < 0 {
+		return requirement, "*"
+	}
+	spec := strings.TrimSpace(requirement[i:])
+	if strings.HasPrefix(spec, "[") || strings.HasPrefix(spec, ";") {
+		return requirement[:i], "*"
+	}
+	return requirement[:i], spec
+}
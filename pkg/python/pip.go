@@ -0,0 +1,108 @@
+package python
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// pipIndexArgs returns the pip arguments for the configured package index, extra
+// indexes, trusted hosts, proxy and CA bundle
+func (p *PythonSetup) pipIndexArgs() []string {
+	cfg := p.Config
+	if cfg == nil {
+		return nil
+	}
+
+	var args []string
+	if cfg.Pip.IndexURL != "" {
+		args = append(args, "--index-url", cfg.Pip.IndexURL)
+	}
+	for _, url := range cfg.Pip.ExtraIndexURLs {
+		args = append(args, "--extra-index-url", url)
+	}
+	for _, host := range cfg.Pip.TrustedHosts {
+		args = append(args, "--trusted-host", host)
+	}
+	if cfg.Proxy != "" {
+		args = append(args, "--proxy", cfg.Proxy)
+	}
+	if cfg.CABundle != "" {
+		args = append(args, "--cert", cfg.CABundle)
+	}
+	return args
+}
+
+// WritePipConfig writes the configured index, proxy and CA bundle settings into a virtual
+// environment's own pip configuration file, so pip run inside the venv uses them too.
+// Nothing is written when no settings are configured.
+func (p *PythonSetup) WritePipConfig(venvDir string) error {
+	cfg := p.Config
+	if cfg == nil {
+		return nil
+	}
+
+	var lines []string
+	if cfg.Pip.IndexURL != "" {
+		lines = append(lines, "index-url = "+cfg.Pip.IndexURL)
+	}
+	if len(cfg.Pip.ExtraIndexURLs) > 0 {
+		lines = append(lines, "extra-index-url =\n    "+strings.Join(cfg.Pip.ExtraIndexURLs, "\n    "))
+	}
+	if len(cfg.Pip.TrustedHosts) > 0 {
+		lines = append(lines, "trusted-host =\n    "+strings.Join(cfg.Pip.TrustedHosts, "\n    "))
+	}
+	if cfg.Proxy != "" {
+		lines = append(lines, "proxy = "+cfg.Proxy)
+	}
+	if cfg.CABundle != "" {
+		lines = append(lines, "cert = "+cfg.CABundle)
+	}
+	if len(lines) == 0 {
+		return nil
+	}
+
+	// pip reads pip.ini inside a venv on Windows and pip.conf elsewhere
+	name := "pip.conf"
+	if runtime.GOOS == "windows" {
+		name = "pip.ini"
+	}
+
+	content := "[global]\n" + strings.Join(lines, "\n") + "\n"
+	if err := os.WriteFile(filepath.Join(venvDir, name), []byte(content), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %v", name, err)
+	}
+	return nil
+}
+
+// pipIndexEnv returns the environment variables carrying the configured package index,
+// proxy and CA bundle to tools that run pip or resolve packages themselves, such as
+// Pipenv and uv
+func (p *PythonSetup) pipIndexEnv() []string {
+	cfg := p.Config
+	if cfg == nil {
+		return nil
+	}
+
+	var env []string
+	if cfg.Pip.IndexURL != "" {
+		env = append(env, "PIP_INDEX_URL="+cfg.Pip.IndexURL, "UV_DEFAULT_INDEX="+cfg.Pip.IndexURL)
+	}
+	if len(cfg.Pip.ExtraIndexURLs) > 0 {
+		urls := strings.Join(cfg.Pip.ExtraIndexURLs, " ")
+		env = append(env, "PIP_EXTRA_INDEX_URL="+urls, "UV_INDEX="+urls)
+	}
+	if len(cfg.Pip.TrustedHosts) > 0 {
+		hosts := strings.Join(cfg.Pip.TrustedHosts, " ")
+		env = append(env, "PIP_TRUSTED_HOST="+hosts, "UV_INSECURE_HOST="+hosts)
+	}
+	if cfg.Proxy != "" {
+		env = append(env, "HTTP_PROXY="+cfg.Proxy, "HTTPS_PROXY="+cfg.Proxy)
+	}
+	if cfg.CABundle != "" {
+		env = append(env, "PIP_CERT="+cfg.CABundle, "REQUESTS_CA_BUNDLE="+cfg.CABundle, "SSL_CERT_FILE="+cfg.CABundle)
+	}
+	return env
+}
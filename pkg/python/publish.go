@@ -0,0 +1,300 @@
+package python
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"html"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"devstation-cli/pkg/config"
+)
+
+// DistDir is where built distributions are written, relative to the project
+const DistDir = "dist"
+
+// Build builds the project's sdist and wheel with python -m build, which installs the build
+// backend into an isolated environment, then validates their metadata with twine check.
+// It returns the paths of the new distributions.
+func (p *PythonSetup) Build(projectDir string, venv Venv) ([]string, error) {
+	if err := p.ensureVenvModule(venv, "build", "build"); err != nil {
+		return nil, err
+	}
+	if err := p.ensureVenvModule(venv, "twine", "twine"); err != nil {
+		return nil, err
+	}
+
+	// Build into an empty directory first so exactly the new files are checked and reported
+	outDir, err := os.MkdirTemp("", "devstation-build-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(outDir)
+
+	fmt.Println("Building sdist and wheel...")
+	if err := p.runVenvPython(venv, projectDir, "-m", "build", "--outdir", outDir, "."); err != nil {
+		return nil, fmt.Errorf("build failed: %v", err)
+	}
+
+	built, err := distributions(outDir)
+	if err != nil {
+		return nil, err
+	}
+	if len(built) == 0 {
+		return nil, fmt.Errorf("build produced no distributions")
+	}
+
+	fmt.Println("Checking distribution metadata...")
+	if err := p.runVenvPython(venv, projectDir, append([]string{"-m", "twine", "check", "--strict"}, built...)...); err != nil {
+		return nil, fmt.Errorf("twine check failed: %v", err)
+	}
+
+	distDir := filepath.Join(projectDir, DistDir)
+	if err := os.MkdirAll(distDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create %s: %v", distDir, err)
+	}
+	var paths []string
+	for _, file := range built {
+		target := filepath.Join(distDir, filepath.Base(file))
+		if err := copyFile(file, target); err != nil {
+			return nil, err
+		}
+		paths = append(paths, target)
+	}
+	return paths, nil
+}
+
+// Publish uploads the distributions in the project's dist directory to a configured
+// repository. Repositories whose URL is a local directory or file:// URL are written as a
+// PEP 503 simple index that pip can install from; others are uploaded with twine.
+func (p *PythonSetup) Publish(projectDir string, venv Venv, repository string) ([]string, error) {
+	repo, ok := config.Current().Repositories[repository]
+	if !ok || repo.URL == "" {
+		return nil, fmt.Errorf("repository %q is not configured; add it with 'devstation config set repository.%s.url URL-or-directory'", repository, repository)
+	}
+
+	dir, local, err := localIndexDir(repo.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := distributions(filepath.Join(projectDir, DistDir))
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no distributions in %s; run 'devstation build' first", filepath.Join(projectDir, DistDir))
+	}
+
+	if local {
+		return publishToDirectory(dir, files)
+	}
+
+	if err := p.ensureVenvModule(venv, "twine", "twine"); err != nil {
+		return nil, err
+	}
+	args := []string{"-m", "twine", "upload", "--skip-existing", "--repository-url", repo.URL}
+	if repo.Username != "" {
+		args = append(args, "--username", repo.Username)
+	}
+	cmd := exec.Command(venv.Python(), append(args, files...)...)
+	cmd.Dir = projectDir
+	cmd.Env = append(os.Environ(), p.pipIndexEnv()...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("twine upload to %s failed: %v", repo.URL, err)
+	}
+	return files, nil
+}
+
+// localIndexDir returns the directory of a repository URL that names a local directory: a
+// path or a file:// URL. http and https URLs are uploaded to with twine, and any other scheme
+// is rejected rather than mistaken for a directory name.
+func localIndexDir(repoURL string) (string, bool, error) {
+	// C:\index parses as a URL with the scheme "c"
+	if filepath.IsAbs(repoURL) {
+		return repoURL, true, nil
+	}
+	u, err := url.Parse(repoURL)
+	if err != nil {
+		return "", false, fmt.Errorf("invalid repository URL %q: %v", repoURL, err)
+	}
+	switch u.Scheme {
+	case "":
+		return repoURL, true, nil
+	case "http", "https":
+		return "", false, nil
+	case "file":
+		if u.Host != "" && u.Host != "localhost" {
+			return "", false, fmt.Errorf("invalid repository URL %q: file URLs must name a local directory", repoURL)
+		}
+		path := u.Path
+		// file:///C:/index has the path "/C:/index"
+		if runtime.GOOS == "windows" && len(path) > 2 && path[0] == '/' && path[2] == ':' {
+			path = path[1:]
+		}
+		return filepath.FromSlash(path), true, nil
+	}
+	return "", false, fmt.Errorf("invalid repository URL %q: use an http(s) URL, a file:// URL or a directory path", repoURL)
+}
+
+// publishToDirectory copies distributions into <dir>/<normalized-name>/ and writes the PEP 503
+// index.html pages pip reads with --index-url file:///dir, the layout pypiserver also serves.
+// Like a real index it refuses to replace an existing file with different content.
+func publishToDirectory(dir string, files []string) ([]string, error) {
+	var published []string
+	projects := make(map[string]bool)
+	for _, file := range files {
+		name, err := distributionProject(filepath.Base(file))
+		if err != nil {
+			return published, err
+		}
+		projectDir := filepath.Join(dir, NormalizePackageName(name))
+		if err := os.MkdirAll(projectDir, 0755); err != nil {
+			return published, fmt.Errorf("failed to create %s: %v", projectDir, err)
+		}
+		projects[projectDir] = true
+
+		target := filepath.Join(projectDir, filepath.Base(file))
+		if existing, err := os.ReadFile(target); err == nil {
+			content, err := os.ReadFile(file)
+			if err != nil {
+				return published, err
+			}
+			if !bytes.Equal(existing, content) {
+				return published, fmt.Errorf("%s already exists in the repository with different content; bump the version", filepath.Base(file))
+			}
+			fmt.Printf("Skipping %s (already published)\n", filepath.Base(file))
+			continue
+		}
+		if err := copyFile(file, target); err != nil {
+			return published, err
+		}
+		published = append(published, target)
+	}
+
+	for projectDir := range projects {
+		if err := writeProjectIndex(projectDir); err != nil {
+			return published, err
+		}
+	}
+	return published, writeRootIndex(dir)
+}
+
+// writeRootIndex writes the PEP 503 root page of a directory index, linking every project
+// directory that has distributions
+func writeRootIndex(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	var links []string
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		if files, err := distributions(filepath.Join(dir, entry.Name())); err != nil || len(files) == 0 {
+			continue
+		}
+		name := html.EscapeString(entry.Name())
+		links = append(links, fmt.Sprintf("<a href=\"%s/\">%s</a>", name, name))
+	}
+	return writeIndexPage(filepath.Join(dir, "index.html"), "Simple index", links)
+}
+
+// writeProjectIndex writes the PEP 503 page of a project directory, linking each distribution
+// with a #sha256= fragment so pip verifies the download
+func writeProjectIndex(projectDir string) error {
+	files, err := distributions(projectDir)
+	if err != nil {
+		return err
+	}
+	var links []string
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		sum := sha256.Sum256(content)
+		name := html.EscapeString(filepath.Base(file))
+		links = append(links, fmt.Sprintf("<a href=\"%s#sha256=%s\">%s</a>", name, hex.EncodeToString(sum[:]), name))
+	}
+	return writeIndexPage(filepath.Join(projectDir, "index.html"), "Links for "+filepath.Base(projectDir), links)
+}
+
+// writeIndexPage writes a simple index HTML page with one link per line
+func writeIndexPage(path, title string, links []string) error {
+	var page strings.Builder
+	page.WriteString("<!DOCTYPE html>\n<html>\n  <head>\n    <meta name=\"pypi:repository-version\" content=\"1.0\">\n")
+	fmt.Fprintf(&page, "    <title>%s</title>\n  </head>\n  <body>\n    <h1>%s</h1>\n", html.EscapeString(title), html.EscapeString(title))
+	for _, link := range links {
+		fmt.Fprintf(&page, "    %s<br>\n", link)
+	}
+	page.WriteString("  </body>\n</html>\n")
+	if err := os.WriteFile(path, []byte(page.String()), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %v", path, err)
+	}
+	return nil
+}
+
+// distributionProject extracts the project name from a wheel or sdist file name
+func distributionProject(filename string) (string, error) {
+	var stem string
+	switch {
+	case strings.HasSuffix(filename, ".whl"):
+		// name-version(-build)?-python-abi-platform.whl; wheel names never contain "-"
+		stem = strings.SplitN(filename, "-", 2)[0]
+	case strings.HasSuffix(filename, ".tar.gz"):
+		// name-version.tar.gz; the version never contains "-"
+		base := strings.TrimSuffix(filename, ".tar.gz")
+		if i := strings.LastIndex(base, "-"); i > 0 {
+			stem = base[:i]
+		}
+	}
+	if stem == "" {
+		return "", fmt.Errorf("%s is not a wheel or sdist", filename)
+	}
+	return stem, nil
+}
+
+// distributions lists the wheels and sdists in a directory
+func distributions(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if !entry.IsDir() && (strings.HasSuffix(name, ".whl") || strings.HasSuffix(name, ".tar.gz")) {
+			files = append(files, filepath.Join(dir, name))
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// copyFile copies a file, replacing the target
+func copyFile(src, dst string) error {
+	content, err := os.ReadFile(src)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(dst, content, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %v", dst, err)
+	}
+	return nil
+}
@@ -0,0 +1,179 @@
+package python
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestDistributionProject(t *testing.T) {
+	tests := []struct {
+		filename string
+		want     string
+		wantErr  bool
+	}{
+		{"my_lib-1.0.0-py3-none-any.whl", "my_lib", false},
+		{"my_lib-1.0.0-1-cp311-cp311-manylinux_2_17_x86_64.whl", "my_lib", false},
+		{"my_lib-1.0.0.tar.gz", "my_lib", false},
+		{"my-lib-1.0.0.tar.gz", "my-lib", false},
+		{"my.lib-2.0rc1.tar.gz", "my.lib", false},
+		{"nodash.tar.gz", "", true},
+		{"my_lib-1.0.0.zip", "", true},
+		{"README.md", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.filename, func(t *testing.T) {
+			got, err := distributionProject(tt.filename)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("distributionProject(%q) error = %v, wantErr %v", tt.filename, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("distributionProject(%q) = %q, want %q", tt.filename, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPublishToDirectory(t *testing.T) {
+	dist := t.TempDir()
+	wheel := writeDistribution(t, dist, "My_Lib-1.0.0-py3-none-any.whl", "wheel")
+	sdist := writeDistribution(t, dist, "my_lib-1.0.0.tar.gz", "sdist")
+	index := t.TempDir()
+
+	published, err := publishToDirectory(index, []string{sdist, wheel})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		filepath.Join(index, "my-lib", "my_lib-1.0.0.tar.gz"),
+		filepath.Join(index, "my-lib", "My_Lib-1.0.0-py3-none-any.whl"),
+	}
+	if !reflect.DeepEqual(published, want) {
+		t.Errorf("published %v, want %v", published, want)
+	}
+
+	root := readFile(t, filepath.Join(index, "index.html"))
+	if !strings.Contains(root, `<a href="my-lib/">my-lib</a>`) {
+		t.Errorf("root index does not link the project:\n%s", root)
+	}
+	page := readFile(t, filepath.Join(index, "my-lib", "index.html"))
+	for _, file := range []string{wheel, sdist} {
+		link := filepath.Base(file) + "#sha256=" + sha256Hex(t, file)
+		if !strings.Contains(page, `<a href="`+link+`">`) {
+			t.Errorf("project index does not link %s:\n%s", link, page)
+		}
+	}
+
+	// Publishing the same files again skips them
+	published, err = publishToDirectory(index, []string{sdist, wheel})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(published) != 0 {
+		t.Errorf("republishing published %v, want nothing", published)
+	}
+
+	// A second project is added to the root index
+	other := writeDistribution(t, dist, "other-0.1.tar.gz", "other")
+	if _, err := publishToDirectory(index, []string{other}); err != nil {
+		t.Fatal(err)
+	}
+	root = readFile(t, filepath.Join(index, "index.html"))
+	if !strings.Contains(root, `<a href="my-lib/">`) || !strings.Contains(root, `<a href="other/">`) {
+		t.Errorf("root index does not link both projects:\n%s", root)
+	}
+
+	// Changed content under a published name is refused and leaves the index alone
+	writeDistribution(t, dist, "my_lib-1.0.0.tar.gz", "rebuilt")
+	_, err = publishToDirectory(index, []string{sdist})
+	if err == nil || !strings.Contains(err.Error(), "different content") {
+		t.Fatalf("publishing changed content: error = %v, want a different content error", err)
+	}
+	if got := readFile(t, filepath.Join(index, "my-lib", "my_lib-1.0.0.tar.gz")); got != "sdist" {
+		t.Errorf("published sdist was overwritten with %q", got)
+	}
+	if got := readFile(t, filepath.Join(index, "my-lib", "index.html")); got != page {
+		t.Errorf("project index changed after a refused publish:\n%s", got)
+	}
+}
+
+func TestPublishToDirectoryInvalidFile(t *testing.T) {
+	file := writeDistribution(t, t.TempDir(), "notes.txt", "")
+	if _, err := publishToDirectory(t.TempDir(), []string{file}); err == nil {
+		t.Error("publishToDirectory() accepted a file that is not a distribution")
+	}
+}
+
+func TestLocalIndexDir(t *testing.T) {
+	tests := []struct {
+		name      string
+		url       string
+		wantDir   string
+		wantLocal bool
+		wantErr   bool
+	}{
+		{"https", "https://pypi.example.com/simple", "", false, false},
+		{"http", "http://localhost:8080/", "", false, false},
+		{"relative path", "index", "index", true, false},
+		{"relative path with dots", "../shared/index", "../shared/index", true, false},
+		{"absolute path", "/srv/index", "/srv/index", true, false},
+		{"file URL", "file:///srv/index", filepath.FromSlash("/srv/index"), true, false},
+		{"file URL with localhost", "file://localhost/srv/index", filepath.FromSlash("/srv/index"), true, false},
+		{"file URL with a remote host", "file://fileserver/index", "", false, true},
+		{"ftp", "ftp://files.example.com/simple", "", false, true},
+		{"misspelled scheme", "htps://pypi.example.com/simple", "", false, true},
+		{"host without scheme", "pypi.example.com:8080/simple", "", false, true},
+	}
+	if runtime.GOOS == "windows" {
+		tests = append(tests, []struct {
+			name      string
+			url       string
+			wantDir   string
+			wantLocal bool
+			wantErr   bool
+		}{
+			{"drive path", `C:\index`, `C:\index`, true, false},
+			{"drive path with slashes", "C:/index", "C:/index", true, false},
+			{"file URL with a drive", "file:///C:/index", `C:\index`, true, false},
+		}...)
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir, local, err := localIndexDir(tt.url)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("localIndexDir(%q) error = %v, wantErr %v", tt.url, err, tt.wantErr)
+			}
+			if dir != tt.wantDir || local != tt.wantLocal {
+				t.Errorf("localIndexDir(%q) = %q, %v, want %q, %v", tt.url, dir, local, tt.wantDir, tt.wantLocal)
+			}
+		})
+	}
+}
+
+func writeDistribution(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(content)
+}
+
+func sha256Hex(t *testing.T, path string) string {
+	sum := sha256.Sum256([]byte(readFile(t, path)))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,48 @@
+package python
+
+import (
+	"strings"
+
+	"devstation-cli/pkg/project"
+)
+
+// BuildBackend describes a PEP 517 build backend a project can be scaffolded with
+type BuildBackend struct {
+	Requires string // Build requirement for [build-system]
+	Module   string // build-backend module
+}
+
+// BuildBackends lists the supported build backends by name
+var BuildBackends = map[string]BuildBackend{
+	"setuptools": {Requires: "setuptools>=61.0", Module: "setuptools.build_meta"},
+	"hatchling":  {Requires: "hatchling>=1.18", Module: "hatchling.build"},
+	"flit":       {Requires: "flit_core>=3.4,<4", Module: "flit_core.buildapi"},
+	"poetry":     {Requires: "poetry-core>=2.0", Module: "poetry.core.masonry.api"},
+}
+
+// DefaultBuildBackend is used when no backend is selected
+const DefaultBuildBackend = "setuptools"
+
+// MinimumPythonVersion is the oldest Python version scaffolded projects support
+const MinimumPythonVersion = "3.9"
+
+// ProjectOptions controls how a Python project is scaffolded
+type ProjectOptions struct {
+	Backend    string   // Build backend name; defaults to the project manager's backend
+	Template   string   // Project template name; defaults to DefaultTemplate
+	Manager    string   // Project manager name; defaults to DefaultProjectManager
+	PreCommit  bool     // Add pre-commit hooks and flake8 configuration
+	NoInstall  bool     // Skip installing the project and its dependencies into the new venv
+	Matrix     []string // Python versions to scaffold a test matrix for, e.g. ["3.9", "3.12"]
+	MatrixTool string   // Tool the test matrix is written for, nox or tox; defaults to DefaultMatrixTool
+
+	project.Options // Scaffolding mode, author and license
+}
+
+// tomlList formats strings as a TOML array, one item per line
+func tomlList(items []string) string {
+	if len(items) == 0 {
+		return "[]"
+	}
+	return "[\n    \"" + strings.Join(items, "\",\n    \"") + "\",\n]"
+}
@@ -1,31 +1,70 @@
 package python
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
 
+	"devstation-cli/pkg/config"
 	"devstation-cli/pkg/installer"
+	"devstation-cli/pkg/project"
 )
 
 // PythonSetup handles Python development environment setup
 type PythonSetup struct {
 	PackageManager installer.PackageManager
+	Interpreter    string         // Path of the Python interpreter to run; empty uses the default
+	Version        string         // Python version to install; empty installs the latest
+	WheelDir       string         // Local wheelhouse to install from instead of the package index
+	Config         *config.Config // Package index, proxy and CA bundle settings
+}
+
+// DevelopmentTools lists the system packages installed alongside Python
+var DevelopmentTools = []string{
+	"git",
+	"vscode", // Visual Studio Code
 }
 
 // NewPythonSetup creates a new Python setup instance
 func NewPythonSetup(pm installer.PackageManager) *PythonSetup {
-	return &PythonSetup{PackageManager: pm}
+	return &PythonSetup{
+		PackageManager: pm,
+		Config:         config.Current(),
+	}
+}
+
+// Python returns the interpreter to run, resolving the default on first use: discovering it
+// probes every installed interpreter, which commands given --python or a venv never need
+func (p *PythonSetup) Python() string {
+	if p.Interpreter == "" {
+		p.Interpreter = DefaultInterpreter()
+	}
+	return p.Interpreter
 }
 
-// InstallPython installs Python and essential tools
+// InstallPython installs Python and the global Python tools
 func (p *PythonSetup) InstallPython() error {
 	fmt.Println("=== Setting up Python Development Environment ===")
 	
 	// Install Python
-	if err := p.PackageManager.Install("python"); err != nil {
-		return fmt.Errorf("failed to install Python: %v", err)
+	if p.Version != "" {
+		interpreter, err := p.InstallVersion(p.Version)
+		if err != nil {
+			return err
+		}
+		p.Interpreter = interpreter.Path
+	} else {
+		if err := p.PackageManager.Install("python"); err != nil {
+			return fmt.Errorf("failed to install Python: %v", err)
+		}
+		if interpreter, err := QueryInterpreter(p.Python()); err == nil {
+			if err := RegisterInterpreter(interpreter, false); err != nil {
+				fmt.Printf("Warning: Failed to record Python interpreter: %v\n", err)
+			}
+		}
 	}
 	
 	// Install pip (usually comes with Python, but ensure it's available)
@@ -33,9 +72,9 @@ func (p *PythonSetup) InstallPython() error {
 		return fmt.Errorf("failed to ensure pip is installed: %v", err)
 	}
 	
-	// Install essential Python packages
-	if err := p.installEssentialPackages(); err != nil {
-		return fmt.Errorf("failed to install essential packages: %v", err)
+	// Install Python tools into isolated environments
+	if err := p.installTools(); err != nil {
+		return fmt.Errorf("failed to install Python tools: %v", err)
 	}
 	
 	fmt.Println("✓ Python development environment setup complete!")
@@ -46,12 +85,7 @@ func (p *PythonSetup) InstallPython() error {
 func (p *PythonSetup) InstallPythonTools() error {
 	fmt.Println("=== Installing Python Development Tools ===")
 	
-	tools := []string{
-		"git",
-		"vscode", // Visual Studio Code
-	}
-	
-	for _, tool := range tools {
+	for _, tool := range DevelopmentTools {
 		if err := p.PackageManager.Install(tool); err != nil {
 			fmt.Printf("Warning: Failed to install %s: %v\n", tool, err)
 		}
@@ -63,11 +97,16 @@ func (p *PythonSetup) InstallPythonTools() error {
 // ensurePipInstalled checks if pip is available and installs it if needed
 func (p *PythonSetup) ensurePipInstalled() error {
 	// Check if pip is available
-	cmd := exec.Command("python", "-m", "pip", "--version")
+	cmd := exec.Command(p.Python(), "-m", "pip", "--version")
 	if err := cmd.Run(); err != nil {
 		fmt.Println("Installing pip...")
+		scopeArgs, err := p.pipScopeArgs()
+		if err != nil {
+			return err
+		}
+		
 		// Download and install pip
-		cmd := exec.Command("python", "-m", "ensurepip", "--upgrade")
+		cmd := exec.Command(p.Python(), append([]string{"-m", "ensurepip", "--upgrade"}, scopeArgs...)...)
 		cmd.Stdout = os.Stdout
 		cmd.Stderr = os.Stderr
 		return cmd.Run()
@@ -77,182 +116,319 @@ func (p *PythonSetup) ensurePipInstalled() error {
 	return nil
 }
 
-// installEssentialPackages installs essential Python packages
-func (p *PythonSetup) installEssentialPackages() error {
-	fmt.Println("Installing essential Python packages...")
-	
-	packages := []string{
-		"virtualenv",     // Virtual environment management
-		"pip-tools",      // Package dependency management
-		"black",          // Code formatter
-		"flake8",         // Linting
-		"pytest",         // Testing framework
-		"requests",       // HTTP library
-		"numpy",          // Scientific computing
-		"pandas",         // Data analysis
-		"jupyter",        // Interactive notebooks
+// pipInstallArgs builds the arguments for "python -m pip install", including the install
+// scope and, in offline mode, the local wheelhouse
+func (p *PythonSetup) pipInstallArgs(extra ...string) ([]string, error) {
+	scopeArgs, err := p.pipScopeArgs()
+	if err != nil {
+		return nil, err
 	}
 	
+	args := append([]string{"-m", "pip", "install"}, scopeArgs...)
+	if p.WheelDir != "" {
+		args = append(args, "--no-index", "--find-links", p.WheelDir)
+	} else {
+		args = append(args, p.pipIndexArgs()...)
+	}
+	return append(args, extra...), nil
+}
+
+// DownloadPackages downloads packages and their dependencies into dir for offline installs,
+// returning the packages that could not be downloaded
+func (p *PythonSetup) DownloadPackages(dir string, packages []string) []string {
+	var failed []string
 	for _, pkg := range packages {
-		fmt.Printf("Installing %s...\n", pkg)
-		cmd := exec.Command("python", "-m", "pip", "install", pkg)
+		fmt.Printf("Downloading %s...\n", pkg)
+		args := append([]string{"-m", "pip", "download", "--dest", dir}, p.pipIndexArgs()...)
+		cmd := exec.Command(p.Python(), append(args, pkg)...)
 		cmd.Stdout = os.Stdout
 		cmd.Stderr = os.Stderr
 		if err := cmd.Run(); err != nil {
-			fmt.Printf("Warning: Failed to install %s: %v\n", pkg, err)
+			fmt.Printf("Warning: Failed to download %s: %v\n", pkg, err)
+			failed = append(failed, pkg)
 		}
 	}
+	return failed
+}
+
+// pipScopeArgs returns the pip install arguments matching the current privilege level:
+// nothing inside a virtual environment or an elevated shell, --user otherwise. Running pip
+// as root against the system interpreter is refused.
+func (p *PythonSetup) pipScopeArgs() ([]string, error) {
+	if p.inVirtualEnv() {
+		return nil, nil
+	}
+	if installer.IsRoot() {
+		return nil, fmt.Errorf("refusing to run pip as root against the system Python interpreter; re-run without sudo or activate a virtual environment")
+	}
+	if !installer.IsElevated() {
+		return []string{"--user"}, nil
+	}
+	return nil, nil
+}
+
+// inVirtualEnv reports whether the python on PATH belongs to a virtual environment
+func (p *PythonSetup) inVirtualEnv() bool {
+	cmd := exec.Command(p.Python(), "-c", "import sys; print(sys.prefix != sys.base_prefix)")
+	output, err := cmd.Output()
+	if err != nil {
+		return false
+	}
+	return strings.TrimSpace(string(output)) == "True"
+}
+
+// outdatedPackages lists the packages in the interpreter's environment that have a newer release available
+func (p *PythonSetup) outdatedPackages() ([]installer.PackageVersion, error) {
+	args := append([]string{"-m", "pip", "list", "--outdated", "--format=json"}, p.pipIndexArgs()...)
+	cmd := exec.Command(p.Python(), args...)
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("pip list failed: %v", err)
+	}
 	
-	return nil
+	var outdated []struct {
+		Name          string `json:"name"`
+		Version       string `json:"version"`
+		LatestVersion string `json:"latest_version"`
+	}
+	if err := json.Unmarshal(output, &outdated); err != nil {
+		return nil, fmt.Errorf("failed to parse pip output: %v", err)
+	}
+	
+	var versions []installer.PackageVersion
+	for _, pkg := range outdated {
+		versions = append(versions, installer.PackageVersion{
+			Name:      NormalizePackageName(pkg.Name),
+			Current:   pkg.Version,
+			Available: pkg.LatestVersion,
+		})
+	}
+	
+	return versions, nil
 }
 
-// CreateProjectStructure creates a basic Python project structure
-func (p *PythonSetup) CreateProjectStructure(projectName string) error {
-	fmt.Printf("Creating Python project structure for '%s'...\n", projectName)
+// NormalizePackageName normalizes a Python distribution name as described in PEP 503
+func NormalizePackageName(name string) string {
+	name = strings.ToLower(name)
+	return strings.NewReplacer("_", "-", ".", "-").Replace(name)
+}
+
+// scaffold is a Python project rendered from its templates
+type scaffold struct {
+	template    ProjectTemplate
+	managerName string
+	manager     ProjectManager
+	files       map[string]string // Keyed by path relative to the project directory
+}
+
+// RenderProject renders the files of a new Python project without writing them, keyed by
+// path relative to the project directory
+func (p *PythonSetup) RenderProject(projectName string, opts ProjectOptions) (map[string]string, error) {
+	s, err := p.render(projectName, opts)
+	return s.files, err
+}
+
+// render validates the project options and renders the project's templates
+func (p *PythonSetup) render(projectName string, opts ProjectOptions) (scaffold, error) {
+	name, err := project.ParseName(projectName)
+	if err != nil {
+		return scaffold{}, err
+	}
+	if pythonKeywords[name.ImportName] {
+		return scaffold{}, fmt.Errorf("invalid project name %q: %s is a Python keyword and cannot be imported", projectName, name.ImportName)
+	}
+	
+	var template ProjectTemplate
+	templateName := opts.Template
+	if user := opts.UserTemplate; user != nil {
+		if user.Type != project.KindPython {
+			return scaffold{}, fmt.Errorf("template %s is a %s template, not a Python one", user.Name, user.Type)
+		}
+		template = ProjectTemplate{Description: user.Description, Dependencies: user.Dependencies, DevDependencies: user.DevDependencies, Scripts: user.Scripts}
+		templateName = user.Name
+	} else {
+		if template, err = lookupTemplate(templateName); err != nil {
+			return scaffold{}, err
+		}
+		if templateName == "" {
+			templateName = DefaultTemplate
+		}
+	}
+	if opts.PreCommit {
+		template.DevDependencies = append(append([]string{}, template.DevDependencies...), preCommitDevDependencies...)
+	}
+	matrix, err := ParseMatrix(opts.Matrix)
+	if err != nil {
+		return scaffold{}, err
+	}
+	matrixTool := opts.MatrixTool
+	if matrixTool == "" {
+		matrixTool = DefaultMatrixTool
+	}
+	if len(matrix) > 0 {
+		if matrixTool != "nox" && matrixTool != "tox" {
+			return scaffold{}, fmt.Errorf("unknown matrix tool %q; expected %s", matrixTool, strings.Join(MatrixTools, " or "))
+		}
+		template.DevDependencies = append(append([]string{}, template.DevDependencies...), matrixTool)
+	}
+	managerName := opts.Manager
+	if managerName == "" {
+		managerName = DefaultProjectManager
+	}
+	manager, err := lookupProjectManager(managerName)
+	if err != nil {
+		return scaffold{}, err
+	}
+	backendName := opts.Backend
+	if backendName == "" {
+		backendName = manager.Backend
+	}
+	backend, ok := BuildBackends[backendName]
+	if !ok {
+		return scaffold{}, fmt.Errorf("unknown build backend %q; expected setuptools, hatchling, flit or poetry", backendName)
+	}
 	
-	// Create project directory
-	if err := os.MkdirAll(projectName, 0755); err != nil {
-		return fmt.Errorf("failed to create project directory: %v", err)
+	options := TemplateOptions{
+		Template:          templateName,
+		Manager:           managerName,
+		Backend:           backendName,
+		BuildRequires:     backend.Requires,
+		BuildBackend:      backend.Module,
+		Dependencies:      template.Dependencies,
+		DevDependencies:   template.devDependencies(),
+		Scripts:           template.scripts(projectName),
+		PythonVersion:     MinimumPythonVersion,
+		Matrix:            matrix,
+		MatrixTool:        matrixTool,
+		Workflow:          managerWorkflow(managerName, manager),
+		KernelName:        KernelName(projectName),
+		KernelDisplayName: KernelDisplayName(projectName),
+	}
+	if p.Config != nil {
+		options.IndexURL = p.Config.Pip.IndexURL
+	}
+	data := project.NewData(name, opts.Options, options)
+	files, err := renderProject(data, templateLayers(options, opts.PreCommit, opts.UserTemplate == nil), opts.UserTemplate)
+	if err != nil {
+		return scaffold{}, err
+	}
+	return scaffold{template: template, managerName: managerName, manager: manager, files: files}, nil
+}
+
+// CreateProjectStructure creates a Python project with a pyproject.toml and a src/ layout
+func (p *PythonSetup) CreateProjectStructure(projectName string, opts ProjectOptions) error {
+	s, err := p.render(projectName, opts)
+	if err != nil {
+		return err
 	}
+	template, managerName, manager := s.template, s.managerName, s.manager
+	fmt.Printf("Creating Python project structure for '%s'...\n", projectName)
 	
-	// Create subdirectories
+	// Subdirectories, including those that start out empty
 	dirs := []string{
-		filepath.Join(projectName, "src"),
+		filepath.Join(projectName, "src", project.ImportName(projectName)),
 		filepath.Join(projectName, "tests"),
 		filepath.Join(projectName, "docs"),
 	}
-	
-	for _, dir := range dirs {
-		if err := os.MkdirAll(dir, 0755); err != nil {
-			return fmt.Errorf("failed to create directory %s: %v", dir, err)
-		}
+	files := make(map[string]string, len(s.files))
+	for path, content := range s.files {
+		files[filepath.Join(projectName, path)] = content
 	}
 	
-	// Create essential files
-	files := map[string]string{
-		filepath.Join(projectName, "requirements.txt"):     "# Add your dependencies here\n",
-		filepath.Join(projectName, "README.md"):            fmt.Sprintf("# %s\n\nDescription of your project.\n", projectName),
-		filepath.Join(projectName, ".gitignore"):           pythonGitignore,
-		filepath.Join(projectName, "src", "__init__.py"):   "",
-		filepath.Join(projectName, "tests", "__init__.py"): "",
-		filepath.Join(projectName, "setup.py"):             generateSetupPy(projectName),
+	if err := project.Scaffold(projectName, dirs, files, opts.Mode); err != nil {
+		return err
 	}
 	
-	for filename, content := range files {
-		if err := os.WriteFile(filename, []byte(content), 0644); err != nil {
-			return fmt.Errorf("failed to create file %s: %v", filename, err)
+	// Create virtual environment
+	var venv Venv
+	if managerName == DefaultProjectManager {
+		venv = p.createPipVenv(projectName, template, opts)
+	} else {
+		venv = p.createManagerVenv(projectName, manager, opts)
+	}
+	if opts.PreCommit {
+		if opts.NoInstall || !venv.Exists() {
+			fmt.Printf("Install the pre-commit hooks later with 'pre-commit install' in %s\n", projectName)
+		} else if err := InstallPreCommitHooks(projectName, venv); err != nil {
+			fmt.Printf("Warning: %v\n", err)
 		}
 	}
-	
-	// Create virtual environment
-	fmt.Println("Creating virtual environment...")
-	cmd := exec.Command("python", "-m", "venv", filepath.Join(projectName, "venv"))
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	if err := cmd.Run(); err != nil {
-		fmt.Printf("Warning: Failed to create virtual environment: %v\n", err)
+	if template.Kernel && !opts.NoInstall && venv.Exists() {
+		fmt.Println("Registering Jupyter kernel...")
+		if err := p.RegisterKernel(venv, KernelName(projectName), KernelDisplayName(projectName)); err != nil {
+			fmt.Printf("Warning: %v\n", err)
+			fmt.Printf("Run 'devstation venv kernel' in %s to retry\n", projectName)
+		}
 	}
-	
+
 	fmt.Printf("✓ Python project '%s' created successfully!\n", projectName)
-	fmt.Printf("To activate the virtual environment, run:\n")
-	fmt.Printf("  cd %s\n", projectName)
-	fmt.Printf("  venv\\Scripts\\activate\n")
+	if venv.Exists() {
+		PrintActivation(projectName, venv)
+	}
 	
 	return nil
 }
 
-// generateSetupPy generates a basic setup.py file
-func generateSetupPy(projectName string) string {
-	return fmt.Sprintf(`from setuptools import setup, find_packages
+// createPipVenv creates the project's venv with python -m venv and, unless disabled,
+// installs the project into it
+func (p *PythonSetup) createPipVenv(projectName string, template ProjectTemplate, opts ProjectOptions) Venv {
+	fmt.Println("Creating virtual environment...")
+	venv, err := ProjectVenv(projectName)
+	if err == nil {
+		err = p.CreateVenv(venv)
+	}
+	if err != nil {
+		fmt.Printf("Warning: %v\n", err)
+		return venv
+	}
+	if opts.NoInstall {
+		return venv
+	}
 
-setup(
-    name="%s",
-    version="0.1.0",
-    description="A Python project",
-    packages=find_packages(where="src"),
-    package_dir={"": "src"},
-    python_requires=">=3.6",
-    install_requires=[
-        # Add your dependencies here
-    ],
-    extras_require={
-        "dev": [
-            "pytest",
-            "black",
-            "flake8",
-        ],
-    },
-)
-`, projectName)
+	dependencies := append(append([]string{}, template.Dependencies...), template.devDependencies()...)
+	failures, err := p.BootstrapVenv(venv, projectName, dependencies)
+	if len(failures) > 0 {
+		fmt.Printf("Warning: %d dependencies could not be installed:\n", len(failures))
+		for _, failure := range failures {
+			fmt.Printf("  ✗ %s: %v\n", failure.Package, failure.Err)
+		}
+	}
+	if err != nil {
+		fmt.Printf("Warning: %v\n", err)
+	}
+	if len(failures) > 0 || err != nil {
+		fmt.Printf("Fix the problems above, then run 'devstation venv recreate' in %s\n", projectName)
+	}
+	return venv
 }
 
-// pythonGitignore contains a comprehensive .gitignore for Python projects
-const pythonGitignore = `# Byte-compiled / optimized / DLL files
-__pycache__/
-*.py[cod]
-*$py.class
-
-# C extensions
-*.so
-
-# Distribution / packaging
-.Python
-build/
-develop-eggs/
-dist/
-downloads/
-eggs/
-.eggs/
-lib/
-lib64/
-parts/
-sdist/
-var/
-wheels/
-pip-wheel-metadata/
-share/python-wheels/
-*.egg-info/
-.installed.cfg
-*.egg
-MANIFEST
-
-# PyInstaller
-*.manifest
-*.spec
-
-# Installer logs
-pip-log.txt
-pip-delete-this-directory.txt
-
-# Unit test / coverage reports
-htmlcov/
-.tox/
-.nox/
-.coverage
-.coverage.*
-.cache
-nosetests.xml
-coverage.xml
-*.cover
-*.py,cover
-.hypothesis/
-.pytest_cache/
-
-# Virtual environments
-venv/
-env/
-ENV/
-env.bak/
-venv.bak/
+// createManagerVenv lets the project manager create the in-project venv and lock file
+func (p *PythonSetup) createManagerVenv(projectName string, manager ProjectManager, opts ProjectOptions) Venv {
+	venv := Venv{Dir: filepath.Join(projectName, DefaultVenvName)}
+	install := strings.Join(manager.Install, " ")
+	if opts.NoInstall {
+		fmt.Printf("Run '%s' in %s to create the virtual environment\n", install, projectName)
+		return venv
+	}
 
-# IDE
-.vscode/
-.idea/
-*.swp
-*.swo
-*~
+	if err := p.EnsureProjectManager(opts.Manager); err != nil {
+		fmt.Printf("Warning: %v\n", err)
+		return venv
+	}
+	fmt.Printf("Running %s...\n", install)
+	if err := p.runProjectManager(manager, projectName, manager.Install); err != nil {
+		fmt.Printf("Warning: %v\n", err)
+		fmt.Printf("Fix the problem above, then run '%s' in %s\n", install, projectName)
+	}
+	return venv
+}
 
-# OS
-.DS_Store
-Thumbs.db
-`
+// pythonKeywords are the reserved words a package cannot be named after
+var pythonKeywords = map[string]bool{
+	"and": true, "as": true, "assert": true, "async": true, "await": true, "break": true,
+	"class": true, "continue": true, "def": true, "del": true, "elif": true, "else": true,
+	"except": true, "finally": true, "for": true, "from": true, "global": true, "if": true,
+	"import": true, "in": true, "is": true, "lambda": true, "nonlocal": true, "not": true, "or": true,
+	"pass": true, "raise": true, "return": true, "try": true, "while": true, "with": true,
+	"yield": true,
+}
@@ -0,0 +1,226 @@
+package python
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"devstation-cli/pkg/config"
+	"devstation-cli/pkg/project"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata/golden")
+
+func TestRenderProjectGolden(t *testing.T) {
+	type testCase struct {
+		name   string
+		config *config.Config
+		opts   ProjectOptions
+	}
+	var cases []testCase
+	for _, template := range TemplateNames() {
+		for _, manager := range []string{"pip", "poetry", "pipenv"} {
+			cases = append(cases, testCase{
+				name: template + "-" + manager,
+				opts: ProjectOptions{Template: template, Manager: manager},
+			})
+		}
+	}
+	cases = append(cases,
+		testCase{name: "lib-pip-pre-commit", opts: ProjectOptions{PreCommit: true}},
+		testCase{name: "lib-pip-license", opts: ProjectOptions{Options: project.Options{License: "MIT"}}},
+		testCase{name: "cli-hatch-bsd-license", opts: ProjectOptions{Template: "cli", Manager: "hatch", Options: project.Options{License: "BSD-3-Clause"}}},
+		testCase{name: "flask-uv-matrix-nox", opts: ProjectOptions{Template: "flask", Manager: "uv", Matrix: []string{"3.10", "3.11"}}},
+		testCase{name: "lib-hatch-flit-matrix-tox", opts: ProjectOptions{Manager: "hatch", Backend: "flit", Matrix: []string{"3.9", "3.12"}, MatrixTool: "tox"}},
+		testCase{
+			name:   "fastapi-pipenv-index",
+			config: &config.Config{Pip: config.PipConfig{IndexURL: "https://pypi.example.corp/simple"}},
+			opts:   ProjectOptions{Template: "fastapi", Manager: "pipenv", PreCommit: true},
+		},
+		testCase{
+			name:   "datascience-poetry-index",
+			config: &config.Config{Pip: config.PipConfig{IndexURL: "https://pypi.example.corp/simple"}},
+			opts:   ProjectOptions{Template: "datascience", Manager: "poetry"},
+		},
+	)
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.opts.Author == "" {
+				tc.opts.Author = "Jane Doe"
+			}
+			p := &PythonSetup{Config: tc.config}
+			files, err := p.RenderProject("my-project", tc.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkGolden(t, filepath.Join("testdata", "golden", tc.name), files)
+		})
+	}
+}
+
+func TestRenderProjectErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		project string
+		opts    ProjectOptions
+		want    string
+	}{
+		{"keyword", "class", ProjectOptions{}, "Python keyword"},
+		{"template", "app", ProjectOptions{Template: "nope"}, "unknown template"},
+		{"manager", "app", ProjectOptions{Manager: "nope"}, "unknown project manager"},
+		{"backend", "app", ProjectOptions{Backend: "nope"}, "unknown build backend"},
+		{"matrix", "app", ProjectOptions{Matrix: []string{"3.8"}}, "older than"},
+		{"matrix tool", "app", ProjectOptions{Matrix: []string{"3.11"}, MatrixTool: "nope"}, "unknown matrix tool"},
+		{"license", "app", ProjectOptions{Options: project.Options{License: "nope"}}, "no license text"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := (&PythonSetup{}).RenderProject(tt.project, tt.opts)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("RenderProject() error = %v, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderProjectUserTemplate(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(project.ManifestFile, `{
+  "name": "service",
+  "description": "Team service",
+  "type": "python",
+  "variables": [{"name": "team", "required": true}],
+  "dependencies": ["flask>=3.0"],
+  "dev_dependencies": ["httpx"],
+  "scripts": {"{{command}}": "{{package}}.app:main"}
+}`)
+	write("README.md.tmpl", "# {{.Name}} ({{.Vars.team}})\n")
+	write("src/{{.ImportName}}/app.py.tmpl", "TEAM = \"{{.Vars.team}}\"\n")
+	user, err := project.LoadTemplate(dir, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	opts := ProjectOptions{Options: project.Options{UserTemplate: &user, Vars: map[string]string{"team": "payments"}}}
+	files, err := (&PythonSetup{}).RenderProject("my-service", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := files["README.md"]; got != "# my-service (payments)\n" {
+		t.Errorf("README.md = %q, want the user template's version", got)
+	}
+	if got := files[filepath.Join("src", "my_service", "app.py")]; got != "TEAM = \"payments\"\n" {
+		t.Errorf("src/my_service/app.py = %q, want the user template's file", got)
+	}
+	pyproject := files["pyproject.toml"]
+	for _, want := range []string{`"flask>=3.0"`, `"httpx"`, `my-service = "my_service.app:main"`} {
+		if !strings.Contains(pyproject, want) {
+			t.Errorf("pyproject.toml does not contain %s:\n%s", want, pyproject)
+		}
+	}
+	if _, ok := files[filepath.Join("src", "my_service", "__init__.py")]; !ok {
+		t.Error("built-in package __init__.py missing from a project rendered with a user template")
+	}
+
+	if _, err := (&PythonSetup{}).RenderProject("my-service", ProjectOptions{Options: project.Options{UserTemplate: &user}}); err == nil {
+		t.Error("RenderProject() without the template's variables succeeded")
+	}
+	user.Type = project.KindC
+	if _, err := (&PythonSetup{}).RenderProject("my-service", ProjectOptions{Options: project.Options{UserTemplate: &user}}); err == nil {
+		t.Error("RenderProject() with a C template succeeded")
+	}
+}
+
+// goldenYear replaces the current year in rendered files so the golden files stay stable
+const goldenYear = "YYYY"
+
+// checkGolden compares rendered files with the golden tree in dir, or rewrites the tree with
+// -update. .gitignore files are stored as _gitignore so they do not apply to the golden tree.
+func checkGolden(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	year := strconv.Itoa(time.Now().Year())
+	want := make(map[string]string)
+	for path, content := range files {
+		want[goldenPath(path)] = strings.ReplaceAll(content, year, goldenYear)
+	}
+
+	if *update {
+		if err := os.RemoveAll(dir); err != nil {
+			t.Fatal(err)
+		}
+		for path, content := range want {
+			target := filepath.Join(dir, filepath.FromSlash(path))
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(target, []byte(content), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return
+	}
+
+	got := make(map[string]string)
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(dir, path)
+		got[filepath.ToSlash(rel)] = string(content)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("reading golden files (run go test -update to create them): %v", err)
+	}
+
+	var paths []string
+	for path := range want {
+		paths = append(paths, path)
+	}
+	for path := range got {
+		if _, ok := want[path]; !ok {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		rendered, isRendered := want[path]
+		golden, isGolden := got[path]
+		switch {
+		case !isGolden:
+			t.Errorf("%s: rendered but not in the golden tree", path)
+		case !isRendered:
+			t.Errorf("%s: in the golden tree but not rendered", path)
+		case rendered != golden:
+			t.Errorf("%s differs from the golden file:\n%s", path, project.Diff(golden, rendered))
+		}
+	}
+}
+
+// goldenPath returns the slash-separated golden tree path of a rendered file
+func goldenPath(path string) string {
+	path = filepath.ToSlash(path)
+	if dir, base := filepath.Split(path); base == ".gitignore" {
+		return dir + "_gitignore"
+	}
+	return path
+}
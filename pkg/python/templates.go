@@ -0,0 +1,179 @@
+package python
+
+import (
+	"embed"
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+
+	"devstation-cli/pkg/project"
+)
+
+// ProjectTemplate describes the starter code a Python project is scaffolded with, on top
+// of the common layout created by CreateProjectStructure. Its files are rendered from
+// templates/starter/<name>.
+type ProjectTemplate struct {
+	Description     string
+	Dependencies    []string          // Runtime dependencies
+	DevDependencies []string          // Added to the dev extra
+	Scripts         map[string]string // Console scripts: command -> "module:function"
+	Kernel          bool              // Register a Jupyter kernel for the project's venv
+}
+
+// baseDevDependencies are included in the dev extra of every scaffolded project
+var baseDevDependencies = []string{"pytest", "black", "ruff", "mypy"}
+
+// DefaultTemplate is used when no template is selected
+const DefaultTemplate = "lib"
+
+// ProjectTemplates lists the built-in Python project templates by name
+var ProjectTemplates = map[string]ProjectTemplate{
+	"lib": {
+		Description: "Reusable library package",
+	},
+	"cli": {
+		Description: "Command-line application with an argparse entry point",
+		Scripts:     map[string]string{"{{command}}": "{{package}}.cli:main"},
+	},
+	"fastapi": {
+		Description:     "FastAPI web API served by uvicorn",
+		Dependencies:    []string{"fastapi>=0.110", "uvicorn[standard]>=0.29"},
+		DevDependencies: []string{"httpx"},
+	},
+	"flask": {
+		Description:  "Flask web application using an app factory",
+		Dependencies: []string{"flask>=3.0"},
+	},
+	"datascience": {
+		Description:  "Data analysis project with notebooks",
+		Dependencies: []string{"numpy", "pandas", "matplotlib"},
+		DevDependencies: []string{
+			"jupyter",
+			"ipykernel",
+		},
+		Kernel: true,
+	},
+}
+
+// TemplateNames returns the names of the built-in templates in sorted order
+func TemplateNames() []string {
+	var names []string
+	for name := range ProjectTemplates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// lookupTemplate returns a template by name, defaulting to DefaultTemplate
+func lookupTemplate(name string) (ProjectTemplate, error) {
+	if name == "" {
+		name = DefaultTemplate
+	}
+	template, ok := ProjectTemplates[name]
+	if !ok {
+		return ProjectTemplate{}, fmt.Errorf("unknown template %q; expected one of %s", name, strings.Join(TemplateNames(), ", "))
+	}
+	return template, nil
+}
+
+// scripts returns the template's console scripts with the project placeholders filled in
+func (t ProjectTemplate) scripts(projectName string) map[string]string {
+	replacer := strings.NewReplacer("{{command}}", project.DistributionName(projectName), "{{package}}", project.ImportName(projectName))
+	scripts := make(map[string]string, len(t.Scripts))
+	for command, target := range t.Scripts {
+		scripts[replacer.Replace(command)] = replacer.Replace(target)
+	}
+	return scripts
+}
+
+// devDependencies returns the dev extra of a project scaffolded from the template
+func (t ProjectTemplate) devDependencies() []string {
+	return append(append([]string{}, baseDevDependencies...), t.DevDependencies...)
+}
+
+//go:embed all:templates
+var templates embed.FS
+
+// TemplateOptions is the Python part of the data scaffold templates are rendered with,
+// available as {{.Options}}
+type TemplateOptions struct {
+	Template          string            // Project template name
+	Manager           string            // Project manager name
+	Backend           string            // Build backend name
+	BuildRequires     string            // Build requirement for [build-system]
+	BuildBackend      string            // build-backend module
+	Dependencies      []string          // Runtime dependencies
+	DevDependencies   []string          // The dev extra
+	Scripts           map[string]string // Console scripts: command -> "module:function"
+	PythonVersion     string            // Oldest supported Python version, e.g. "3.9"
+	Matrix            []string          // Python versions of the test matrix; empty for none
+	MatrixTool        string            // nox or tox
+	IndexURL          string            // Configured package index; empty for PyPI
+	Workflow          string            // README section describing the manager's workflow
+	KernelName        string            // Jupyter kernel registered for the project's venv
+	KernelDisplayName string
+}
+
+// templateFuncs are available in the Python scaffold templates on top of project.Render's
+var templateFuncs = template.FuncMap{
+	"toml": tomlList,
+	// pytag turns a version into the tag tools use for it, e.g. "3.9" -> "39"
+	"pytag": func(version string) string { return strings.ReplaceAll(version, ".", "") },
+	// pipfile formats a requirement as a Pipfile entry, e.g. httpx = "*"
+	"pipfile": func(requirement string) string {
+		name, spec := splitRequirement(requirement)
+		return name + " = \"" + spec + "\""
+	},
+}
+
+// templateLayers returns the template directories a project is rendered from, in order;
+// files of later layers replace those of earlier ones. starter is false for projects whose
+// starter files come from a user-defined template.
+func templateLayers(opts TemplateOptions, preCommit, starter bool) []string {
+	layers := []string{"templates/base"}
+	if starter {
+		layers = append(layers, "templates/starter/"+opts.Template)
+	}
+	layers = append(layers, "templates/manager/"+opts.Manager)
+	if preCommit {
+		layers = append(layers, "templates/pre-commit")
+	}
+	if len(opts.Matrix) > 0 {
+		layers = append(layers, "templates/matrix/"+opts.MatrixTool)
+	}
+	return layers
+}
+
+// renderProject renders a project's template layers, then the user-defined template if
+// any, and its license into file contents keyed by path relative to the project root
+func renderProject(data project.Data, layers []string, user *project.Template) (map[string]string, error) {
+	files := make(map[string]string)
+	for _, layer := range layers {
+		rendered, err := project.Render(templates, layer, data, templateFuncs)
+		if err != nil {
+			return nil, err
+		}
+		for path, content := range rendered {
+			files[path] = content
+		}
+	}
+	if user != nil {
+		rendered, err := user.Render(data, templateFuncs)
+		if err != nil {
+			return nil, err
+		}
+		for path, content := range rendered {
+			files[path] = content
+		}
+	}
+	license, err := project.LicenseFile(data)
+	if err != nil {
+		return nil, err
+	}
+	if license != "" {
+		files["LICENSE"] = license
+	}
+	return files, nil
+}
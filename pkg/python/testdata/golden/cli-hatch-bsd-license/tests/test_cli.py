@@ -0,0 +1,8 @@
+import pytest
+
+from my_project.cli import main
+
+
+def test_main_greets(capsys: pytest.CaptureFixture[str]) -> None:
+    assert main(["--name", "Tester"]) == 0
+    assert capsys.readouterr().out == "Hello, Tester!\n"
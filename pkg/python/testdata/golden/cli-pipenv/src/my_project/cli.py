@@ -0,0 +1,22 @@
+"""Command-line interface for my-project."""
+
+from __future__ import annotations
+
+import argparse
+from collections.abc import Sequence
+
+
+def build_parser() -> argparse.ArgumentParser:
+    parser = argparse.ArgumentParser(prog="my-project", description="my-project command-line tool")
+    parser.add_argument("--name", default="World", help="who to greet")
+    return parser
+
+
+def main(argv: Sequence[str] | None = None) -> int:
+    args = build_parser().parse_args(argv)
+    print(f"Hello, {args.name}!")
+    return 0
+
+
+if __name__ == "__main__":
+    raise SystemExit(main())
@@ -0,0 +1,10 @@
+import pandas as pd
+
+from my_project.analysis import summarize
+
+
+def test_summarize() -> None:
+    df = pd.DataFrame({"x": [1.0, 2.0, 3.0]})
+    summary = summarize(df)
+    assert summary.loc["count", "x"] == 3
+    assert summary.loc["mean", "x"] == 2.0
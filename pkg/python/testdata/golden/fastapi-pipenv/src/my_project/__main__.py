@@ -0,0 +1,3 @@
+import uvicorn
+
+uvicorn.run("my_project.app:app", reload=True)
@@ -0,0 +1,10 @@
+from fastapi.testclient import TestClient
+
+from my_project.app import app
+
+
+def test_health() -> None:
+    client = TestClient(app)
+    response = client.get("/health")
+    assert response.status_code == 200
+    assert response.json() == {"status": "ok"}
@@ -0,0 +1,8 @@
+from my_project.app import create_app
+
+
+def test_health() -> None:
+    client = create_app().test_client()
+    response = client.get("/health")
+    assert response.status_code == 200
+    assert response.get_json() == {"status": "ok"}
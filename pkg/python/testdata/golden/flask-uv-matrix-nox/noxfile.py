@@ -0,0 +1,9 @@
+import nox
+
+PYTHON_VERSIONS = ["3.10", "3.11"]
+
+
+@nox.session(python=PYTHON_VERSIONS)
+def tests(session: nox.Session) -> None:
+    session.install("-e", ".[dev]")
+    session.run("pytest", *session.posargs)
@@ -0,0 +1,3 @@
+"""my-project package."""
+
+__version__ = "0.1.0"
@@ -0,0 +1,5 @@
+from my_project.core import greet
+
+
+def test_greet() -> None:
+    assert greet("World") == "Hello, World!"
@@ -0,0 +1,182 @@
+package python
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"devstation-cli/pkg/testrun"
+)
+
+// MatrixTools lists the tools a test matrix can be scaffolded for
+var MatrixTools = []string{"nox", "tox"}
+
+// DefaultMatrixTool is used when no matrix tool is selected
+const DefaultMatrixTool = "nox"
+
+// MatrixDir is where devstation keeps the per-version venvs of a project's test matrix
+var MatrixDir = filepath.Join(".devstation", "matrix")
+
+// MatrixResult is the outcome of running the tests with one Python version
+type MatrixResult struct {
+	Version     string
+	Interpreter string        // Interpreter used; empty when none was found
+	Suite       testrun.Suite // Test results; Suite.Err says why the version could not be tested
+}
+
+// Missing reports whether no interpreter was found for the version
+func (r MatrixResult) Missing() bool {
+	return r.Interpreter == ""
+}
+
+// ParseMatrix validates a list of Python versions such as ["3.9", "3.10"] for a test matrix
+func ParseMatrix(versions []string) ([]string, error) {
+	minimum, _ := parseVersion(MinimumPythonVersion)
+
+	var matrix []string
+	seen := make(map[string]bool)
+	for _, version := range versions {
+		version = strings.TrimSpace(version)
+		parsed, err := parseVersion(version)
+		if err != nil || len(parsed) != 2 {
+			return nil, fmt.Errorf("invalid matrix version %q; expected major.minor such as 3.11", version)
+		}
+		if compareVersions(parsed, minimum, 2) < 0 {
+			return nil, fmt.Errorf("Python %s is older than the minimum supported version %s", version, MinimumPythonVersion)
+		}
+		if !seen[version] {
+			seen[version] = true
+			matrix = append(matrix, version)
+		}
+	}
+	return matrix, nil
+}
+
+var (
+	noxVersions = regexp.MustCompile(`(?m)^PYTHON_VERSIONS\s*=\s*\[([^\]]*)\]`)
+	toxEnvlist  = regexp.MustCompile(`(?m)^envlist\s*=\s*(.+)$`)
+	toxPyEnv    = regexp.MustCompile(`^py(\d)(\d+)$`)
+)
+
+// ProjectMatrix reads the Python versions of a project's test matrix from its noxfile.py
+// PYTHON_VERSIONS list or its tox.ini envlist
+func ProjectMatrix(projectDir string) ([]string, error) {
+	if content, err := os.ReadFile(filepath.Join(projectDir, "noxfile.py")); err == nil {
+		if match := noxVersions.FindSubmatch(content); match != nil {
+			var versions []string
+			for _, quoted := range tomlQuotedValue.FindAllStringSubmatch(string(match[1]), -1) {
+				versions = append(versions, quoted[1]+quoted[2])
+			}
+			return ParseMatrix(versions)
+		}
+	}
+
+	if content, err := os.ReadFile(filepath.Join(projectDir, "tox.ini")); err == nil {
+		if match := toxEnvlist.FindSubmatch(content); match != nil {
+			var versions []string
+			for _, env := range strings.Split(string(match[1]), ",") {
+				if parts := toxPyEnv.FindStringSubmatch(strings.TrimSpace(env)); parts != nil {
+					versions = append(versions, parts[1]+"."+parts[2])
+				}
+			}
+			return ParseMatrix(versions)
+		}
+	}
+
+	return nil, fmt.Errorf("no test matrix found in %s (expected PYTHON_VERSIONS in noxfile.py or envlist in tox.ini)", projectDir)
+}
+
+// RunTests runs pytest in the project's venv and collects the results from its JUnit report.
+// The returned error is only set when pytest could not be started at all.
+func RunTests(projectDir string, venv Venv, args []string) (testrun.Suite, error) {
+	suite := testrun.Suite{Name: "pytest"}
+	if !venv.Exists() {
+		return suite, fmt.Errorf("no virtual environment at %s; run 'devstation venv create' first", venv.Dir)
+	}
+	if exec.Command(venv.Python(), "-c", "import pytest").Run() != nil {
+		return suite, fmt.Errorf("pytest is not installed in the virtual environment")
+	}
+
+	report, err := os.CreateTemp("", "devstation-pytest-*.xml")
+	if err != nil {
+		return suite, err
+	}
+	report.Close()
+	defer os.Remove(report.Name())
+
+	// The report option goes last so it wins over one given in args
+	cmd := venv.Command(venv.Python(), append(append([]string{"-m", "pytest"}, args...), "--junitxml="+report.Name())...)
+	cmd.Dir = projectDir
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	start := time.Now()
+	runErr := cmd.Run()
+	suite.Duration = time.Since(start)
+
+	// pytest writes no report when it cannot start; the exit status below covers that case
+	suite.Cases, _ = testrun.ReadJUnit(report.Name())
+
+	// pytest exits 1 for failing tests; other statuses mean the run itself went wrong,
+	// e.g. 2 for interruption or collection errors and 5 when no tests were collected
+	var exitErr *exec.ExitError
+	if errors.As(runErr, &exitErr) && exitErr.ExitCode() == pytestTestsFailed && len(suite.Cases) > 0 {
+		return suite, nil
+	}
+	if runErr != nil {
+		suite.Err = fmt.Errorf("pytest failed: %v", runErr)
+	}
+	return suite, nil
+}
+
+// pytestTestsFailed is pytest's exit status when tests ran and some failed
+const pytestTestsFailed = 1
+
+// RunMatrix runs the tests once per Python version, each in its own venv under MatrixDir
+// created with the discovered interpreter for that version. Versions without an
+// interpreter are reported as missing rather than failing the run early.
+func (p *PythonSetup) RunMatrix(projectDir string, versions []string, args []string) []MatrixResult {
+	var results []MatrixResult
+	for _, version := range versions {
+		result := MatrixResult{Version: version, Suite: testrun.Suite{Name: "py" + version}}
+
+		interpreter, err := FindInterpreter(version)
+		if err != nil {
+			result.Suite.Err = err
+			results = append(results, result)
+			continue
+		}
+		result.Interpreter = interpreter.Path
+
+		fmt.Printf("=== Python %s (%s) ===\n", version, interpreter.Path)
+		venv := Venv{Dir: filepath.Join(projectDir, MatrixDir, "py"+version)}
+		versionSetup := *p
+		versionSetup.Interpreter = interpreter.Path
+		if !venv.Exists() {
+			if err := versionSetup.CreateVenv(venv); err != nil {
+				result.Suite.Err = err
+				results = append(results, result)
+				continue
+			}
+		}
+
+		if err := versionSetup.ForVenv(venv).runPipInstall("--quiet", "-e", projectDir+"[dev]"); err != nil {
+			result.Suite.Err = fmt.Errorf("failed to install the project: %v", err)
+			results = append(results, result)
+			continue
+		}
+
+		suite, err := RunTests(projectDir, venv, args)
+		if err != nil {
+			suite.Err = err
+		}
+		suite.Name = result.Suite.Name
+		result.Suite = suite
+		results = append(results, result)
+	}
+	return results
+}
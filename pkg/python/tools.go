@@ -0,0 +1,359 @@
+package python
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+
+	"devstation-cli/pkg/config"
+	"devstation-cli/pkg/installer"
+)
+
+// GlobalTools lists the Python command-line applications devstation installs for the
+// user. Each one gets its own virtual environment, so they never touch the system
+// interpreter; libraries such as numpy and pandas belong in project venvs instead.
+var GlobalTools = []string{
+	"black",     // Code formatter
+	"flake8",    // Linting
+	"pytest",    // Testing framework
+	"pip-tools", // Dependency locking (pip-compile, pip-sync)
+}
+
+// InstalledTool is a tool environment managed by devstation
+type InstalledTool struct {
+	Name     string   // Normalized package name
+	Version  string   // Installed version
+	Commands []string // Console scripts exposed through shims
+}
+
+// ToolsDir returns the root directory of the tool environments: tools.dir when
+// configured, else devstation/tools under the user's local data directory
+func ToolsDir() (string, error) {
+	if dir := config.Current().Tools.Dir; dir != "" {
+		return dir, nil
+	}
+
+	if runtime.GOOS == "windows" {
+		if local := os.Getenv("LOCALAPPDATA"); local != "" {
+			return filepath.Join(local, "devstation", "tools"), nil
+		}
+	} else if data := os.Getenv("XDG_DATA_HOME"); data != "" {
+		return filepath.Join(data, "devstation", "tools"), nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate home directory: %v", err)
+	}
+	if runtime.GOOS == "windows" {
+		return filepath.Join(home, "AppData", "Local", "devstation", "tools"), nil
+	}
+	return filepath.Join(home, ".local", "share", "devstation", "tools"), nil
+}
+
+// ShimDir returns the directory holding the tool shims, which should be on PATH
+func ShimDir() (string, error) {
+	root, err := ToolsDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(root, "bin"), nil
+}
+
+// toolNamePattern is the PEP 508 project name rule, which also keeps tool environment
+// directory names free of separators and specifiers
+var toolNamePattern = regexp.MustCompile(`(?i)^([a-z0-9]|[a-z0-9][a-z0-9._-]*[a-z0-9])$`)
+
+// ToolName returns the normalized package name of a tool requirement such as "black==24.1"
+// or "uvicorn[standard]", rejecting requirements that do not start with a valid name
+func ToolName(requirement string) (string, error) {
+	name, _ := splitRequirement(strings.TrimSpace(requirement))
+	if i := strings.Index(name, "@"); i >= 0 {
+		name = name[:i]
+	}
+	if !toolNamePattern.MatchString(name) {
+		return "", fmt.Errorf("invalid tool %q: expected a package name with an optional version specifier, e.g. black==24.1", requirement)
+	}
+	return NormalizePackageName(name), nil
+}
+
+// ToolVenv returns the virtual environment a tool is installed into, named after the
+// package name of the requirement
+func ToolVenv(requirement string) (Venv, error) {
+	name, err := ToolName(requirement)
+	if err != nil {
+		return Venv{}, err
+	}
+	root, err := ToolsDir()
+	if err != nil {
+		return Venv{}, err
+	}
+	return Venv{Dir: filepath.Join(root, "envs", name)}, nil
+}
+
+// InstallTool installs a tool requirement such as "black" or "black==24.1" into its own
+// virtual environment and creates shims for its console scripts
+func (p *PythonSetup) InstallTool(requirement string) error {
+	name, err := ToolName(requirement)
+	if err != nil {
+		return err
+	}
+	venv, err := ToolVenv(name)
+	if err != nil {
+		return err
+	}
+
+	if !venv.Exists() {
+		if err := p.CreateVenv(venv); err != nil {
+			return err
+		}
+	}
+	if err := p.ForVenv(venv).runPipInstall(requirement); err != nil {
+		return fmt.Errorf("pip install failed: %v", err)
+	}
+	return linkToolShims(name, venv)
+}
+
+// UpgradeTool upgrades a tool inside its environment, within the version specifier of the
+// requirement if any, and refreshes its shims
+func (p *PythonSetup) UpgradeTool(requirement string) error {
+	name, err := ToolName(requirement)
+	if err != nil {
+		return err
+	}
+	venv, err := ToolVenv(name)
+	if err != nil {
+		return err
+	}
+	if !venv.Exists() {
+		return fmt.Errorf("%s is not installed", name)
+	}
+
+	fmt.Printf("Upgrading %s...\n", name)
+	if err := p.ForVenv(venv).runPipInstall("--upgrade", requirement); err != nil {
+		return err
+	}
+	return linkToolShims(name, venv)
+}
+
+// UninstallTool removes a tool's shims and environment
+func UninstallTool(requirement string) error {
+	name, err := ToolName(requirement)
+	if err != nil {
+		return err
+	}
+	venv, err := ToolVenv(name)
+	if err != nil {
+		return err
+	}
+	if !venv.Exists() {
+		return fmt.Errorf("%s is not installed", name)
+	}
+
+	if err := removeToolShims(venv); err != nil {
+		return err
+	}
+	return venv.Delete()
+}
+
+// InstalledTools lists the tool environments with their versions and commands
+func InstalledTools() ([]InstalledTool, error) {
+	root, err := ToolsDir()
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(filepath.Join(root, "envs"))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var tools []InstalledTool
+	for _, entry := range entries {
+		venv := Venv{Dir: filepath.Join(root, "envs", entry.Name())}
+		if !entry.IsDir() || !venv.Exists() {
+			continue
+		}
+		tool := InstalledTool{Name: entry.Name()}
+		if info, err := toolInfo(entry.Name(), venv); err == nil {
+			tool.Version = info.Version
+			tool.Commands = info.Commands
+		}
+		tools = append(tools, tool)
+	}
+	return tools, nil
+}
+
+// OutdatedTools lists the installed tools that have a newer release available
+func (p *PythonSetup) OutdatedTools() ([]installer.PackageVersion, error) {
+	tools, err := InstalledTools()
+	if err != nil {
+		return nil, err
+	}
+
+	var versions []installer.PackageVersion
+	for _, tool := range tools {
+		venv, err := ToolVenv(tool.Name)
+		if err != nil {
+			return nil, err
+		}
+		outdated, err := p.ForVenv(venv).outdatedPackages()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", tool.Name, err)
+		}
+		for _, version := range outdated {
+			if version.Name == tool.Name {
+				versions = append(versions, version)
+			}
+		}
+	}
+	return versions, nil
+}
+
+// toolInfoScript prints a distribution's version and commands as JSON: its console
+// scripts plus executables it ships as data files in the scripts directory, such as uv
+const toolInfoScript = `import sys, json
+from importlib import metadata
+dist = metadata.distribution(sys.argv[1])
+commands = {ep.name for ep in dist.entry_points if ep.group == "console_scripts"}
+for f in dist.files or []:
+    if len(f.parts) > 1 and f.parts[0] == ".." and f.parts[-2] in ("bin", "Scripts"):
+        commands.add(f.stem if f.suffix == ".exe" else f.name)
+print(json.dumps({"version": dist.version, "commands": sorted(commands)}))`
+
+type toolMetadata struct {
+	Version  string   `json:"version"`
+	Commands []string `json:"commands"`
+}
+
+// toolInfo reads a tool's installed version and console scripts from its environment
+func toolInfo(name string, venv Venv) (toolMetadata, error) {
+	output, err := exec.Command(venv.Python(), "-c", toolInfoScript, name).Output()
+	if err != nil {
+		return toolMetadata{}, fmt.Errorf("failed to read metadata of %s: %v", name, err)
+	}
+
+	var info toolMetadata
+	if err := json.Unmarshal(output, &info); err != nil {
+		return toolMetadata{}, fmt.Errorf("unexpected metadata output for %s: %v", name, err)
+	}
+	return info, nil
+}
+
+// linkToolShims exposes a tool's console scripts in the shim directory: symlinks on
+// Unix, .cmd wrappers on Windows where symlinks need extra privileges
+func linkToolShims(name string, venv Venv) error {
+	info, err := toolInfo(name, venv)
+	if err != nil {
+		return err
+	}
+	shimDir, err := ShimDir()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(shimDir, 0755); err != nil {
+		return fmt.Errorf("failed to create shim directory: %v", err)
+	}
+
+	for _, command := range info.Commands {
+		if runtime.GOOS == "windows" {
+			target := filepath.Join(venv.BinDir(), command+".exe")
+			shim := filepath.Join(shimDir, command+".cmd")
+			if err := os.WriteFile(shim, []byte("@\""+target+"\" %*\r\n"), 0644); err != nil {
+				return fmt.Errorf("failed to create shim %s: %v", shim, err)
+			}
+			continue
+		}
+
+		shim := filepath.Join(shimDir, command)
+		if existing, err := os.Readlink(shim); err == nil && !shimOwnedBy(existing, venv) {
+			fmt.Printf("Warning: %s is provided by another tool (%s); replacing it\n", command, existing)
+		}
+		os.Remove(shim)
+		if err := os.Symlink(filepath.Join(venv.BinDir(), command), shim); err != nil {
+			return fmt.Errorf("failed to create shim %s: %v", shim, err)
+		}
+	}
+	return nil
+}
+
+// shimOwnedBy reports whether a shim target is a command of the venv; the separator keeps
+// envs/black from claiming the commands of envs/black-extra
+func shimOwnedBy(target string, venv Venv) bool {
+	return strings.HasPrefix(target, venv.BinDir()+string(filepath.Separator))
+}
+
+// removeToolShims deletes the shims that point into a tool's environment
+func removeToolShims(venv Venv) error {
+	shimDir, err := ShimDir()
+	if err != nil {
+		return err
+	}
+	entries, err := os.ReadDir(shimDir)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		shim := filepath.Join(shimDir, entry.Name())
+		var target string
+		if runtime.GOOS == "windows" {
+			content, err := os.ReadFile(shim)
+			if err != nil {
+				continue
+			}
+			target = strings.TrimPrefix(string(content), "@\"")
+		} else if target, err = os.Readlink(shim); err != nil {
+			continue
+		}
+		if shimOwnedBy(target, venv) {
+			if err := os.Remove(shim); err != nil {
+				return fmt.Errorf("failed to remove shim %s: %v", shim, err)
+			}
+		}
+	}
+	return nil
+}
+
+// ShimDirOnPath reports whether the shim directory is listed in PATH
+func ShimDirOnPath() bool {
+	shimDir, err := ShimDir()
+	if err != nil {
+		return false
+	}
+	for _, dir := range filepath.SplitList(os.Getenv("PATH")) {
+		if filepath.Clean(dir) == filepath.Clean(shimDir) {
+			return true
+		}
+	}
+	return false
+}
+
+// installTools installs the global tools into isolated environments
+func (p *PythonSetup) installTools() error {
+	fmt.Println("Installing Python tools into isolated environments...")
+
+	for _, tool := range GlobalTools {
+		fmt.Printf("Installing %s...\n", tool)
+		if err := p.InstallTool(tool); err != nil {
+			fmt.Printf("Warning: Failed to install %s: %v\n", tool, err)
+		}
+	}
+
+	if !ShimDirOnPath() {
+		shimDir, _ := ShimDir()
+		fmt.Printf("Add %s to your PATH to use the installed tools\n", shimDir)
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+package python
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"devstation-cli/pkg/config"
+)
+
+func TestToolName(t *testing.T) {
+	tests := []struct {
+		requirement string
+		want        string
+		wantErr     bool
+	}{
+		{"black", "black", false},
+		{"black==24.1", "black", false},
+		{" Black >= 24, < 25 ", "black", false},
+		{"pip-tools", "pip-tools", false},
+		{"Pip_Tools~=7.4", "pip-tools", false},
+		{"zope.interface", "zope-interface", false},
+		{"uvicorn[standard]>=0.29", "uvicorn", false},
+		{"httpie; python_version >= '3.8'", "httpie", false},
+		{"mytool @ https://example.com/mytool-1.0.tar.gz", "mytool", false},
+		{"x", "x", false},
+		{"", "", true},
+		{"==24.1", "", true},
+		{"../black", "", true},
+		{"tools/black", "", true},
+		{`tools\black`, "", true},
+		{"-black", "", true},
+		{"black-", "", true},
+		{".hidden", "", true},
+		{"bl*ck", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.requirement, func(t *testing.T) {
+			got, err := ToolName(tt.requirement)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ToolName(%q) error = %v, wantErr %v", tt.requirement, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ToolName(%q) = %q, want %q", tt.requirement, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestToolVenv(t *testing.T) {
+	current := config.Current()
+	saved := current.Tools
+	defer func() { current.Tools = saved }()
+	root := t.TempDir()
+	current.Tools.Dir = root
+
+	venv, err := ToolVenv("Black==24.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := filepath.Join(root, "envs", "black"); venv.Dir != want {
+		t.Errorf("ToolVenv() = %s, want %s", venv.Dir, want)
+	}
+	if _, err := ToolVenv("../black"); err == nil || !strings.Contains(err.Error(), "invalid tool") {
+		t.Errorf("ToolVenv(\"../black\") error = %v, want an invalid tool error", err)
+	}
+}
+
+func TestShimOwnedBy(t *testing.T) {
+	root := t.TempDir()
+	black := Venv{Dir: filepath.Join(root, "envs", "black")}
+	tests := []struct {
+		target string
+		want   bool
+	}{
+		{filepath.Join(black.BinDir(), "black"), true},
+		{filepath.Join(black.BinDir(), "blackd"), true},
+		{filepath.Join(root, "envs", "black-extra", filepath.Base(black.BinDir()), "black"), false},
+		{filepath.Join(root, "envs", "black") + "-extra", false},
+		{black.BinDir(), false},
+		{filepath.Join(root, "envs", "ruff", filepath.Base(black.BinDir()), "ruff"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			if got := shimOwnedBy(tt.target, black); got != tt.want {
+				t.Errorf("shimOwnedBy(%q) = %v, want %v", tt.target, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRemoveToolShimsKeepsOtherTools(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("shims are .cmd wrappers on Windows")
+	}
+	current := config.Current()
+	saved := current.Tools
+	defer func() { current.Tools = saved }()
+	root := t.TempDir()
+	current.Tools.Dir = root
+
+	black := Venv{Dir: filepath.Join(root, "envs", "black")}
+	extra := Venv{Dir: filepath.Join(root, "envs", "black-extra")}
+	shimDir := filepath.Join(root, "bin")
+	if err := os.MkdirAll(shimDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for shim, target := range map[string]string{
+		"black":       filepath.Join(black.BinDir(), "black"),
+		"black-extra": filepath.Join(extra.BinDir(), "black-extra"),
+	} {
+		if err := os.Symlink(target, filepath.Join(shimDir, shim)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := removeToolShims(black); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Lstat(filepath.Join(shimDir, "black")); !os.IsNotExist(err) {
+		t.Errorf("black shim still exists: %v", err)
+	}
+	if _, err := os.Lstat(filepath.Join(shimDir, "black-extra")); err != nil {
+		t.Errorf("black-extra shim was removed with black: %v", err)
+	}
+}
@@ -0,0 +1,317 @@
+package python
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"devstation-cli/pkg/config"
+)
+
+// DefaultVenvName is the in-project virtual environment directory used when none is configured
+const DefaultVenvName = ".venv"
+
+// Venv is a Python virtual environment directory
+type Venv struct {
+	Dir string
+}
+
+// BinDir returns the directory holding the venv's executables: Scripts on Windows, bin elsewhere
+func (v Venv) BinDir() string {
+	if runtime.GOOS == "windows" {
+		return filepath.Join(v.Dir, "Scripts")
+	}
+	return filepath.Join(v.Dir, "bin")
+}
+
+// Python returns the path of the venv's interpreter
+func (v Venv) Python() string {
+	if runtime.GOOS == "windows" {
+		return filepath.Join(v.BinDir(), "python.exe")
+	}
+	return filepath.Join(v.BinDir(), "python")
+}
+
+// Exists reports whether the venv has been created
+func (v Venv) Exists() bool {
+	_, err := os.Stat(v.Python())
+	return err == nil
+}
+
+// ActivateCommand returns the command that activates the venv in the given shell
+func (v Venv) ActivateCommand(shell string) string {
+	bin := v.BinDir()
+	switch shell {
+	case "fish":
+		return "source " + filepath.Join(bin, "activate.fish")
+	case "powershell", "pwsh":
+		return "& " + quoteIfNeeded(filepath.Join(bin, "Activate.ps1"))
+	case "cmd":
+		return quoteIfNeeded(filepath.Join(bin, "activate.bat"))
+	}
+	// bash, zsh and other POSIX shells, including Git Bash on Windows
+	return "source " + quoteIfNeeded(filepath.ToSlash(filepath.Join(bin, "activate")))
+}
+
+// quoteIfNeeded wraps a path in double quotes when it contains spaces
+func quoteIfNeeded(path string) string {
+	if strings.ContainsAny(path, " \t") {
+		return `"` + path + `"`
+	}
+	return path
+}
+
+// DetectShell guesses the user's interactive shell: bash, zsh, fish, powershell or cmd
+func DetectShell() string {
+	if os.Getenv("FISH_VERSION") != "" {
+		return "fish"
+	}
+	if shell := os.Getenv("SHELL"); shell != "" {
+		name := strings.TrimSuffix(filepath.Base(shell), ".exe")
+		switch name {
+		case "bash", "zsh", "fish", "pwsh":
+			return name
+		}
+		if runtime.GOOS != "windows" {
+			return "bash"
+		}
+	}
+	if runtime.GOOS == "windows" {
+		// cmd.exe defines PROMPT for its children; PowerShell does not
+		if os.Getenv("PROMPT") != "" {
+			return "cmd"
+		}
+		return "powershell"
+	}
+	return "bash"
+}
+
+// ProjectVenv returns the virtual environment used by the project in projectDir. By
+// default it is the .venv directory inside the project; with venv.location set to
+// "central" it lives in a per-project directory under venv.central-dir.
+func ProjectVenv(projectDir string) (Venv, error) {
+	cfg := config.Current().Venv
+
+	if cfg.Location == "central" {
+		root := cfg.CentralDir
+		if root == "" {
+			cacheDir, err := os.UserCacheDir()
+			if err != nil {
+				return Venv{}, fmt.Errorf("failed to locate user cache directory: %v", err)
+			}
+			root = filepath.Join(cacheDir, "devstation", "venvs")
+		}
+
+		// Key the directory by the project's absolute path so same-named projects do not collide
+		abs, err := filepath.Abs(projectDir)
+		if err != nil {
+			return Venv{}, err
+		}
+		sum := sha256.Sum256([]byte(abs))
+		name := filepath.Base(abs) + "-" + hex.EncodeToString(sum[:])[:8]
+		return Venv{Dir: filepath.Join(root, name)}, nil
+	}
+
+	name := cfg.Name
+	if name == "" {
+		name = DefaultVenvName
+	}
+	return Venv{Dir: filepath.Join(projectDir, name)}, nil
+}
+
+// CreateVenv creates a virtual environment with the setup's interpreter and writes the
+// configured pip settings into it
+func (p *PythonSetup) CreateVenv(venv Venv) error {
+	if err := os.MkdirAll(filepath.Dir(venv.Dir), 0755); err != nil {
+		return fmt.Errorf("failed to create directory for virtual environment: %v", err)
+	}
+
+	cmd := exec.Command(p.Python(), "-m", "venv", venv.Dir)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to create virtual environment: %v", err)
+	}
+
+	return p.WritePipConfig(venv.Dir)
+}
+
+// PrintActivation prints how to enter the project and activate its venv in the current shell
+func PrintActivation(projectDir string, venv Venv) {
+	shell := DetectShell()
+
+	// Show in-project venvs relative to the project directory
+	display := venv
+	if rel, err := filepath.Rel(projectDir, venv.Dir); err == nil && !strings.HasPrefix(rel, "..") {
+		display = Venv{Dir: rel}
+	}
+
+	fmt.Printf("To activate the virtual environment (%s), run:\n", shell)
+	fmt.Printf("  cd %s\n", projectDir)
+	fmt.Printf("  %s\n", display.ActivateCommand(shell))
+}
+
+// projectMarkers are the files that identify a Python project root
+var projectMarkers = []string{"pyproject.toml", "requirements.txt", "setup.py"}
+
+// FindProject returns the nearest directory at or above start that contains a
+// pyproject.toml, requirements.txt or setup.py
+func FindProject(start string) (string, error) {
+	dir, err := filepath.Abs(start)
+	if err != nil {
+		return "", err
+	}
+
+	for {
+		for _, marker := range projectMarkers {
+			if _, err := os.Stat(filepath.Join(dir, marker)); err == nil {
+				return dir, nil
+			}
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", fmt.Errorf("no Python project found in %s or its parents (looked for %s)", start, strings.Join(projectMarkers, ", "))
+		}
+		dir = parent
+	}
+}
+
+// ForVenv returns a copy of the setup that runs the venv's interpreter, so pip installs
+// go into the venv with the configured index settings
+func (p *PythonSetup) ForVenv(venv Venv) *PythonSetup {
+	venvSetup := *p
+	venvSetup.Interpreter = venv.Python()
+	return &venvSetup
+}
+
+// InstallProject installs a project's requirements.txt and, if it has a pyproject.toml
+// or setup.py, the project itself in editable mode into the venv
+func (p *PythonSetup) InstallProject(venv Venv, projectDir string) error {
+	venvSetup := p.ForVenv(venv)
+
+	if requirements := filepath.Join(projectDir, "requirements.txt"); fileExists(requirements) {
+		fmt.Println("Installing requirements.txt...")
+		if err := venvSetup.runPipInstall("-r", requirements); err != nil {
+			return fmt.Errorf("failed to install requirements.txt: %v", err)
+		}
+	}
+
+	if fileExists(filepath.Join(projectDir, "pyproject.toml")) || fileExists(filepath.Join(projectDir, "setup.py")) {
+		fmt.Println("Installing project in editable mode...")
+		if err := venvSetup.runPipInstall("-e", projectDir); err != nil {
+			return fmt.Errorf("failed to install project: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// DependencyFailure is a dependency that could not be installed into a venv
+type DependencyFailure struct {
+	Package string
+	Err     error
+}
+
+// BootstrapVenv prepares a new venv for development: it upgrades pip, setuptools and
+// wheel, then installs the project in editable mode with its dev extra. If that install
+// fails, the dependencies are installed one at a time so each failure can be reported,
+// and the project itself is installed without them.
+func (p *PythonSetup) BootstrapVenv(venv Venv, projectDir string, dependencies []string) ([]DependencyFailure, error) {
+	venvSetup := p.ForVenv(venv)
+
+	projectDir, err := filepath.Abs(projectDir)
+	if err != nil {
+		return nil, err
+	}
+
+	fmt.Println("Upgrading pip, setuptools and wheel...")
+	if err := venvSetup.runPipInstall("--upgrade", "pip", "setuptools", "wheel"); err != nil {
+		// The venv still has the pip bundled with the interpreter
+		fmt.Printf("Warning: Failed to upgrade pip, setuptools and wheel: %v\n", err)
+	}
+
+	fmt.Println("Installing project in editable mode with dev dependencies...")
+	if err := venvSetup.runPipInstall("-e", projectDir+"[dev]"); err == nil {
+		return nil, nil
+	}
+
+	fmt.Println("Editable install failed; installing dependencies one at a time...")
+	var failures []DependencyFailure
+	for _, dep := range dependencies {
+		if err := venvSetup.runPipInstall(dep); err != nil {
+			failures = append(failures, DependencyFailure{Package: dep, Err: err})
+		}
+	}
+
+	if err := venvSetup.runPipInstall("--no-deps", "-e", projectDir); err != nil {
+		return failures, fmt.Errorf("failed to install project in editable mode: %v", err)
+	}
+	return failures, nil
+}
+
+// runPipInstall runs "pip install" with the given arguments
+func (p *PythonSetup) runPipInstall(args ...string) error {
+	installArgs, err := p.pipInstallArgs(args...)
+	if err != nil {
+		return err
+	}
+
+	cmd := exec.Command(p.Python(), installArgs...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+// InstalledPackages returns the "name==version" lines of the packages installed in the venv
+func (v Venv) InstalledPackages() ([]string, error) {
+	output, err := exec.Command(v.Python(), "-m", "pip", "list", "--format=freeze", "--disable-pip-version-check").Output()
+	if err != nil {
+		return nil, fmt.Errorf("pip list failed: %v", err)
+	}
+	return strings.Fields(string(output)), nil
+}
+
+// Command builds a command that runs inside the venv without activating it, by putting
+// the venv's executables first on PATH and setting VIRTUAL_ENV
+func (v Venv) Command(name string, args ...string) *exec.Cmd {
+	// Resolve venv executables before falling back to PATH lookup
+	for _, candidate := range []string{filepath.Join(v.BinDir(), name), filepath.Join(v.BinDir(), name+".exe")} {
+		if fileExists(candidate) {
+			name = candidate
+			break
+		}
+	}
+
+	cmd := exec.Command(name, args...)
+	var env []string
+	for _, entry := range os.Environ() {
+		upper := strings.ToUpper(entry)
+		if strings.HasPrefix(upper, "PATH=") || strings.HasPrefix(upper, "PYTHONHOME=") || strings.HasPrefix(upper, "VIRTUAL_ENV=") {
+			continue
+		}
+		env = append(env, entry)
+	}
+	cmd.Env = append(env,
+		"PATH="+v.BinDir()+string(os.PathListSeparator)+os.Getenv("PATH"),
+		"VIRTUAL_ENV="+v.Dir,
+	)
+	return cmd
+}
+
+// Delete removes the venv directory after checking it really is a virtual environment
+func (v Venv) Delete() error {
+	if !fileExists(filepath.Join(v.Dir, "pyvenv.cfg")) {
+		return fmt.Errorf("%s is not a virtual environment (no pyvenv.cfg)", v.Dir)
+	}
+	return os.RemoveAll(v.Dir)
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
@@ -0,0 +1,174 @@
+package testrun
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"time"
+)
+
+// Case is the outcome of a single test
+type Case struct {
+	Name      string
+	Classname string // Module, file or target the test belongs to
+	Duration  time.Duration
+	Failure   string // Failure message; empty when the test passed
+	Skipped   bool
+}
+
+// Suite is the outcome of one test run, e.g. pytest in a venv or CTest in a build directory
+type Suite struct {
+	Name     string
+	Cases    []Case
+	Duration time.Duration
+	Err      error // The run itself failed, e.g. the build broke or the runner crashed
+}
+
+// Counts returns the number of passed, failed and skipped tests
+func (s Suite) Counts() (passed, failed, skipped int) {
+	for _, c := range s.Cases {
+		switch {
+		case c.Skipped:
+			skipped++
+		case c.Failure != "":
+			failed++
+		default:
+			passed++
+		}
+	}
+	return passed, failed, skipped
+}
+
+// Passed reports whether the run completed without failing tests
+func (s Suite) Passed() bool {
+	_, failed, _ := s.Counts()
+	return s.Err == nil && failed == 0
+}
+
+// junitSuites is the JUnit XML report layout understood by CI servers
+type junitSuites struct {
+	XMLName xml.Name     `xml:"testsuites"`
+	Suites  []junitSuite `xml:"testsuite"`
+}
+
+type junitSuite struct {
+	Name     string      `xml:"name,attr"`
+	Tests    int         `xml:"tests,attr"`
+	Failures int         `xml:"failures,attr"`
+	Errors   int         `xml:"errors,attr"`
+	Skipped  int         `xml:"skipped,attr"`
+	Time     float64     `xml:"time,attr"`
+	Cases    []junitCase `xml:"testcase"`
+	// pytest nests its suites inside <testsuites>; older versions write a bare <testsuite>
+	Suites []junitSuite `xml:"testsuite"`
+}
+
+type junitCase struct {
+	Name      string        `xml:"name,attr"`
+	Classname string        `xml:"classname,attr"`
+	Time      float64       `xml:"time,attr"`
+	Failure   *junitMessage `xml:"failure"`
+	Error     *junitMessage `xml:"error"`
+	Skipped   *junitMessage `xml:"skipped"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr,omitempty"`
+	Text    string `xml:",chardata"`
+}
+
+// ReadJUnit reads the test cases from a JUnit XML report
+func ReadJUnit(path string) ([]Case, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var root junitSuite
+	if err := xml.Unmarshal(content, &root); err != nil {
+		return nil, fmt.Errorf("invalid JUnit report %s: %v", path, err)
+	}
+
+	var cases []Case
+	var collect func(suite junitSuite)
+	collect = func(suite junitSuite) {
+		for _, jc := range suite.Cases {
+			c := Case{
+				Name:      jc.Name,
+				Classname: jc.Classname,
+				Duration:  time.Duration(jc.Time * float64(time.Second)),
+			}
+			switch {
+			case jc.Failure != nil:
+				c.Failure = failureMessage(jc.Failure, "failed")
+			case jc.Error != nil:
+				c.Failure = failureMessage(jc.Error, "error")
+			case jc.Skipped != nil:
+				c.Skipped = true
+			}
+			cases = append(cases, c)
+		}
+		for _, nested := range suite.Suites {
+			collect(nested)
+		}
+	}
+	collect(root)
+	return cases, nil
+}
+
+// failureMessage picks the most useful description of a failed test
+func failureMessage(m *junitMessage, fallback string) string {
+	if m.Message != "" {
+		return m.Message
+	}
+	if m.Text != "" {
+		return m.Text
+	}
+	return fallback
+}
+
+// WriteJUnit writes the suites as a JUnit XML report. A suite whose run failed is
+// recorded as an error so CI servers do not mistake it for an empty pass.
+func WriteJUnit(path string, suites []Suite) error {
+	report := junitSuites{}
+	for _, suite := range suites {
+		passed, failed, skipped := suite.Counts()
+		js := junitSuite{
+			Name:     suite.Name,
+			Tests:    passed + failed + skipped,
+			Failures: failed,
+			Skipped:  skipped,
+			Time:     suite.Duration.Seconds(),
+		}
+		for _, c := range suite.Cases {
+			jc := junitCase{Name: c.Name, Classname: c.Classname, Time: c.Duration.Seconds()}
+			switch {
+			case c.Skipped:
+				jc.Skipped = &junitMessage{}
+			case c.Failure != "":
+				jc.Failure = &junitMessage{Message: c.Failure}
+			}
+			js.Cases = append(js.Cases, jc)
+		}
+		if suite.Err != nil {
+			js.Tests++
+			js.Errors++
+			js.Cases = append(js.Cases, junitCase{
+				Name:      suite.Name,
+				Classname: suite.Name,
+				Error:     &junitMessage{Message: suite.Err.Error()},
+			})
+		}
+		report.Suites = append(report.Suites, js)
+	}
+
+	content, err := xml.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+	content = append([]byte(xml.Header), append(content, '\n')...)
+	if err := os.WriteFile(path, content, 0644); err != nil {
+		return fmt.Errorf("failed to write JUnit report %s: %v", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+package testrun
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestJUnitRoundTrip(t *testing.T) {
+	tests := []struct {
+		name   string
+		suites []Suite
+		want   []Case
+	}{
+		{
+			name:   "empty",
+			suites: []Suite{{Name: "pytest"}},
+			want:   nil,
+		},
+		{
+			name: "passed, failed and skipped",
+			suites: []Suite{{
+				Name: "pytest",
+				Cases: []Case{
+					{Name: "test_ok", Classname: "tests.test_app", Duration: 1500 * time.Millisecond},
+					{Name: "test_bad", Classname: "tests.test_app", Failure: "assert 1 == 2"},
+					{Name: "test_later", Classname: "tests.test_app", Skipped: true},
+				},
+			}},
+			want: []Case{
+				{Name: "test_ok", Classname: "tests.test_app", Duration: 1500 * time.Millisecond},
+				{Name: "test_bad", Classname: "tests.test_app", Failure: "assert 1 == 2"},
+				{Name: "test_later", Classname: "tests.test_app", Skipped: true},
+			},
+		},
+		{
+			name: "several suites and a broken run",
+			suites: []Suite{
+				{Name: "3.11", Cases: []Case{{Name: "test_ok", Classname: "tests"}}},
+				{Name: "3.12", Err: errors.New("no interpreter found")},
+			},
+			want: []Case{
+				{Name: "test_ok", Classname: "tests"},
+				{Name: "3.12", Classname: "3.12", Failure: "no interpreter found"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "report.xml")
+			if err := WriteJUnit(path, tt.suites); err != nil {
+				t.Fatal(err)
+			}
+			got, err := ReadJUnit(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ReadJUnit(WriteJUnit()) = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadJUnit(t *testing.T) {
+	tests := []struct {
+		name   string
+		report string
+		want   []Case
+	}{
+		{
+			name: "bare testsuite",
+			report: `<testsuite name="pytest"><testcase classname="t" name="a" time="0.5"/>` +
+				`<testcase classname="t" name="b"><error message="fixture failed"/></testcase></testsuite>`,
+			want: []Case{
+				{Name: "a", Classname: "t", Duration: 500 * time.Millisecond},
+				{Name: "b", Classname: "t", Failure: "fixture failed"},
+			},
+		},
+		{
+			name: "nested testsuites",
+			report: `<testsuites><testsuite name="pytest"><testcase classname="t" name="a">` +
+				`<failure>AssertionError</failure></testcase><testcase classname="t" name="b"><skipped/></testcase>` +
+				`</testsuite></testsuites>`,
+			want: []Case{
+				{Name: "a", Classname: "t", Failure: "AssertionError"},
+				{Name: "b", Classname: "t", Skipped: true},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "report.xml")
+			if err := os.WriteFile(path, []byte(tt.report), 0644); err != nil {
+				t.Fatal(err)
+			}
+			got, err := ReadJUnit(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ReadJUnit() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadJUnitInvalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "report.xml")
+	if err := os.WriteFile(path, []byte("not xml"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ReadJUnit(path); err == nil || !strings.Contains(err.Error(), "invalid JUnit report") {
+		t.Errorf("ReadJUnit() error = %v, want an invalid report error", err)
+	}
+}
+
+func TestSuitePassed(t *testing.T) {
+	tests := []struct {
+		name  string
+		suite Suite
+		want  bool
+	}{
+		{"no cases", Suite{}, true},
+		{"skipped only", Suite{Cases: []Case{{Skipped: true}}}, true},
+		{"failure", Suite{Cases: []Case{{}, {Failure: "Not Run"}}}, false},
+		{"run error", Suite{Cases: []Case{{}}, Err: errors.New("ctest failed")}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.suite.Passed(); got != tt.want {
+				t.Errorf("Passed() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}